@@ -0,0 +1,49 @@
+// Package audit keeps actor and method of the call in context so every profile mutation can be recorded with them
+package audit
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/distuurbia/profile/internal/grpcctx"
+	"google.golang.org/grpc"
+)
+
+// SystemActor is recorded for mutations made outside of gRPC calls like background workers
+const SystemActor = "system"
+
+// Call contains who made the mutation and through which method
+type Call struct {
+	Actor  string
+	Method string
+}
+
+type callKey struct{}
+
+// WithCall returns copy of context with actor and method of the call
+func WithCall(ctx context.Context, call Call) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// FromContext returns actor and method of the call, context without them belongs to SystemActor
+func FromContext(ctx context.Context) Call {
+	if call, ok := ctx.Value(callKey{}).(Call); ok {
+		return call
+	}
+	return Call{Actor: SystemActor}
+}
+
+// UnaryServerInterceptor attaches actor and method of unary call to its context, it goes after authentication
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(WithCall(ctx, Call{Actor: auth.Caller(ctx), Method: info.FullMethod}), req)
+	}
+}
+
+// StreamServerInterceptor attaches actor and method of stream to its context, it goes after authentication
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := WithCall(ss.Context(), Call{Actor: auth.Caller(ss.Context()), Method: info.FullMethod})
+		return handler(srv, grpcctx.WrapServerStream(ss, ctx))
+	}
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+const testMethod = "/ProfileService/DeleteProfile"
+
+func TestFromContextWithoutCall(t *testing.T) {
+	require.Equal(t, Call{Actor: SystemActor}, FromContext(context.Background()))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	var call Call
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		call = FromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "admin-console"})
+	_, err := interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, Call{Actor: "sub:admin-console", Method: testMethod}, call)
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 51234}})
+	_, err = interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, Call{Actor: "addr:10.0.0.7", Method: testMethod}, call)
+
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, Call{Actor: "unknown", Method: testMethod}, call)
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testSecret   = "secret"
+	testSubject  = "3f6b2a6e-1c9b-4e55-9f3e-0b2d6c1d5a10"
+	testIssuer   = "auth"
+	deleteMethod = "/ProfileService/DeleteProfile"
+	healthMethod = "/grpc.health.v1.Health/Check"
+)
+
+func claims(scope string, expiresIn time.Duration) Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   testSubject,
+			Issuer:    testIssuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+		Scope: scope,
+	}
+}
+
+func signHS256(t *testing.T, c jwt.Claims, secret string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func newVerifier(publicKeys map[string]ed25519.PublicKey) *Verifier {
+	return NewVerifier([]byte(testSecret), publicKeys, testIssuer, "", time.Second)
+}
+
+func TestVerifyHS256(t *testing.T) {
+	v := newVerifier(nil)
+
+	principal, err := v.Verify(signHS256(t, claims("admin profile:service", time.Minute), testSecret))
+	require.NoError(t, err)
+	require.Equal(t, testSubject, principal.Subject)
+	require.True(t, principal.HasScope(ScopeAdmin))
+	require.True(t, principal.HasScope(ScopeService))
+
+	_, err = v.Verify(signHS256(t, claims("", -time.Minute), testSecret))
+	require.Error(t, err)
+	_, err = v.Verify(signHS256(t, claims("", time.Minute), "other secret"))
+	require.Error(t, err)
+	_, err = v.Verify(signHS256(t, jwt.RegisteredClaims{Subject: testSubject, Issuer: testIssuer}, testSecret))
+	require.ErrorContains(t, err, "expiration")
+
+	wrongIssuer := claims("", time.Minute)
+	wrongIssuer.Issuer = "someone"
+	_, err = v.Verify(signHS256(t, wrongIssuer, testSecret))
+	require.Error(t, err)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims(ScopeAdmin, time.Minute)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = v.Verify(unsigned)
+	require.Error(t, err)
+}
+
+func TestVerifyEdDSAWithJWKS(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	jwksJSON := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"rsa","n":"AQAB","e":"AQAB"},{"kty":"OKP","crv":"Ed25519","kid":"key-1","x":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(publicKey))
+	require.NoError(t, os.WriteFile(path, []byte(jwksJSON), 0o600))
+
+	keys, err := LoadJWKS(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	v := NewVerifier(nil, keys, testIssuer, "", time.Second)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims(ScopeService, time.Minute))
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(privateKey)
+	require.NoError(t, err)
+	principal, err := v.Verify(signed)
+	require.NoError(t, err)
+	require.True(t, principal.HasScope(ScopeService))
+
+	token.Header["kid"] = "key-2"
+	signed, err = token.SignedString(privateKey)
+	require.NoError(t, err)
+	_, err = v.Verify(signed)
+	require.Error(t, err)
+
+	_, err = v.Verify(signHS256(t, claims("", time.Minute), ""))
+	require.Error(t, err)
+}
+
+type idRequest struct {
+	id string
+}
+
+func (r *idRequest) GetId() string {
+	return r.id
+}
+
+func callUnary(t *testing.T, token string, method string, req interface{}) (*Principal, error) {
+	interceptor := UnaryServerInterceptor(newVerifier(nil), Policies{
+		deleteMethod: AnyOf(RequireScope(ScopeAdmin), SubjectIsID()),
+		healthMethod: Public(),
+	})
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationKey, "Bearer "+token))
+	}
+	var principal *Principal
+	_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		principal, _ = FromContext(ctx)
+		return nil, nil
+	})
+	return principal, err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	owner := signHS256(t, claims("", time.Minute), testSecret)
+	admin := signHS256(t, Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "admin", Issuer: testIssuer,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}, Scope: ScopeAdmin}, testSecret)
+
+	principal, err := callUnary(t, owner, deleteMethod, &idRequest{id: testSubject})
+	require.NoError(t, err)
+	require.Equal(t, testSubject, principal.Subject)
+	_, err = callUnary(t, admin, deleteMethod, &idRequest{id: testSubject})
+	require.NoError(t, err)
+
+	_, err = callUnary(t, owner, deleteMethod, &idRequest{id: "other"})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = callUnary(t, "", deleteMethod, &idRequest{id: testSubject})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = callUnary(t, "garbage", deleteMethod, &idRequest{id: testSubject})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = callUnary(t, "", healthMethod, nil)
+	require.NoError(t, err)
+	_, err = callUnary(t, "", "/ProfileService/Unknown", nil)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = callUnary(t, owner, "/ProfileService/Unknown", nil)
+	require.NoError(t, err)
+}
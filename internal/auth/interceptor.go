@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/distuurbia/profile/internal/grpcctx"
+	"github.com/distuurbia/profile/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationKey = "authorization"
+	bearerPrefix     = "bearer "
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns copy of context with authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns authenticated caller of the call
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// Caller returns subject of the bearer token, or address of the peer for calls without token
+func Caller(ctx context.Context) string {
+	if principal, ok := FromContext(ctx); ok {
+		return "sub:" + principal.Subject
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return "addr:" + p.Addr.String()
+		}
+		return "addr:" + host
+	}
+	return "unknown"
+}
+
+// UnaryServerInterceptor authenticates bearer token of unary call and checks policy of its method,
+// methods without policy require any valid token
+func UnaryServerInterceptor(v *Verifier, policies Policies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, v, policies, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates bearer token of stream and checks policy of its method without request
+func StreamServerInterceptor(v *Verifier, policies Policies) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), v, policies, info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		return handler(srv, grpcctx.WrapServerStream(ss, ctx))
+	}
+}
+
+func authorize(ctx context.Context, v *Verifier, policies Policies, method string, req interface{}) (context.Context, error) {
+	policy, ok := policies[method]
+	if !ok {
+		policy = Authenticated()
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		// calls without token have no principal, only policies like Public allow them
+		if policy(ctx, nil, req) {
+			return ctx, nil
+		}
+		return ctx, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+	principal, err := v.Verify(token)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("auth -> authorize -> %v", err)
+		return ctx, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	if !policy(ctx, principal, req) {
+		return ctx, status.Errorf(codes.PermissionDenied, "%s isn't allowed to call %s", principal.Subject, method)
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+// bearerToken returns token from authorization metadata of the call
+func bearerToken(ctx context.Context) (string, bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationKey)
+	if len(values) == 0 || len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return values[0][len(bearerPrefix):], true
+}
@@ -0,0 +1,57 @@
+package auth
+
+import "context"
+
+// Scopes of tokens that are checked by policies of the service
+const (
+	ScopeAdmin   = "admin"
+	ScopeService = "profile:service"
+)
+
+// Policy decides whether principal may make the call with the request, principal is nil for calls without token
+// and request is nil for streams
+type Policy func(ctx context.Context, principal *Principal, req interface{}) bool
+
+// Policies maps full method names to their policies
+type Policies map[string]Policy
+
+// Public allows calls without token, e.g. of health checks
+func Public() Policy {
+	return func(context.Context, *Principal, interface{}) bool {
+		return true
+	}
+}
+
+// Authenticated allows calls of any principal with valid token
+func Authenticated() Policy {
+	return func(_ context.Context, principal *Principal, _ interface{}) bool {
+		return principal != nil
+	}
+}
+
+// RequireScope allows calls of principals whose token has the scope
+func RequireScope(scope string) Policy {
+	return func(_ context.Context, principal *Principal, _ interface{}) bool {
+		return principal != nil && principal.HasScope(scope)
+	}
+}
+
+// SubjectIsID allows calls whose request id is the subject of the token, so profiles can manage only themselves
+func SubjectIsID() Policy {
+	return func(_ context.Context, principal *Principal, req interface{}) bool {
+		withID, ok := req.(interface{ GetId() string })
+		return ok && principal != nil && withID.GetId() != "" && withID.GetId() == principal.Subject
+	}
+}
+
+// AnyOf allows calls allowed by at least one of the policies
+func AnyOf(policies ...Policy) Policy {
+	return func(ctx context.Context, principal *Principal, req interface{}) bool {
+		for _, policy := range policies {
+			if policy(ctx, principal, req) {
+				return true
+			}
+		}
+		return false
+	}
+}
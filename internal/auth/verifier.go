@@ -0,0 +1,126 @@
+// Package auth verifies bearer tokens of callers and enforces per-RPC access policies
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are JWT claims issued to callers of the service, Scope is space-separated list of scopes
+type Claims struct {
+	jwt.RegisteredClaims
+	Scope string `json:"scope,omitempty"`
+}
+
+// Principal is authenticated caller extracted from the token
+type Principal struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasScope checks whether the token of principal was issued with the scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier checks signature and claims of HS256 tokens signed with shared secret and EdDSA tokens signed with keys from JWKS
+type Verifier struct {
+	secret    []byte
+	publicKey map[string]ed25519.PublicKey
+	parser    *jwt.Parser
+}
+
+// NewVerifier creates Verifier, issuer and audience are checked only if they aren't empty
+func NewVerifier(secret []byte, publicKeys map[string]ed25519.PublicKey, issuer, audience string, leeway time.Duration) *Verifier {
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodEdDSA.Alg()}), jwt.WithLeeway(leeway)}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	return &Verifier{secret: secret, publicKey: publicKeys, parser: jwt.NewParser(opts...)}
+}
+
+// Verify parses the token and returns its principal if signature and claims are valid
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	var claims Claims
+	_, err := v.parser.ParseWithClaims(token, &claims, v.key)
+	if err != nil {
+		return nil, fmt.Errorf("Verifier -> Verify -> %w", err)
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("Verifier -> Verify -> error: token has no expiration time")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("Verifier -> Verify -> error: token has no subject")
+	}
+	return &Principal{Subject: claims.Subject, Scopes: strings.Fields(claims.Scope)}, nil
+}
+
+// key picks verification key by algorithm and key id of the token
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if len(v.secret) == 0 {
+			return nil, errors.New("HS256 tokens aren't accepted without secret key")
+		}
+		return v.secret, nil
+	case jwt.SigningMethodEdDSA.Alg():
+		kid, _ := token.Header["kid"].(string)
+		key, ok := v.publicKey[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// jwks is a JSON Web Key Set with Ed25519 public keys
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		Kid string `json:"kid"`
+		X   string `json:"x"`
+	} `json:"keys"`
+}
+
+// LoadJWKS reads Ed25519 public keys from JWKS file by their key id, keys of other types are skipped
+func LoadJWKS(path string) (map[string]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadJWKS -> %w", err)
+	}
+	var set jwks
+	if err = json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("LoadJWKS -> %w", err)
+	}
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Kty != "OKP" || key.Crv != "Ed25519" {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("LoadJWKS -> error: invalid Ed25519 key %q", key.Kid)
+		}
+		keys[key.Kid] = ed25519.PublicKey(x)
+	}
+	return keys, nil
+}
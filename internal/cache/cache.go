@@ -0,0 +1,245 @@
+// Package cache keeps results of hot reads of ProfileRepository in memory or Redis
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/primary"
+	"github.com/distuurbia/profile/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+// Backend is a storage of cached entries, it's implemented by LRUBackend and RedisBackend
+type Backend interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Options contains settings of CachedRepository. Entries of existing rows live for TTL
+// and entries of missing rows live for NegativeTTL, reads of the wrapped repository on misses take up to LoadTimeout
+type Options struct {
+	Prefix      string
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	LoadTimeout time.Duration
+}
+
+// Markers of cached entries: the row exists and the value follows, or the row doesn't exist
+const (
+	found    byte = 'v'
+	notFound byte = 'n'
+)
+
+// CachedRepository is service.ProfileRepository that serves GetRefreshTokenByID and GetPasswordAndIDByUsername from Backend
+// and reads the wrapped repository on misses, concurrent misses of one key make single read. Writes go to the wrapped
+// repository and then drop entries they change, a read that was in flight during the write drops the entry it filled,
+// so it can't bring back the value the write replaced. LRUBackend is dropped only on the replica that made the write,
+// so several replicas should share RedisBackend
+type CachedRepository struct {
+	service.ProfileRepository
+	backend Backend
+	opts    Options
+	group   singleflight.Group
+
+	mu      sync.Mutex
+	loading map[string]*flight
+}
+
+// flight is a read of the wrapped repository that fills the entry, it's stale if the entry was invalidated meanwhile
+type flight struct {
+	stale bool
+}
+
+// NewCachedRepository creates an object of *CachedRepository
+func NewCachedRepository(r service.ProfileRepository, backend Backend, opts Options) *CachedRepository {
+	return &CachedRepository{ProfileRepository: r, backend: backend, opts: opts, loading: make(map[string]*flight)}
+}
+
+// GetPasswordAndIDByUsername returns cached id and hash of the password of the profile, missing profile is cached too
+func (c *CachedRepository) GetPasswordAndIDByUsername(ctx context.Context, username string) (profileID uuid.UUID, password []byte, err error) {
+	value, err := c.load(ctx, c.usernameKey(username), func(ctx context.Context) ([]byte, error) {
+		profileID, password, err := c.ProfileRepository.GetPasswordAndIDByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		return append(profileID[:], password...), nil
+	})
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("CachedRepository -> GetPasswordAndIDByUsername -> %w", err)
+	}
+	if len(value) < len(profileID) {
+		return uuid.Nil, nil, fmt.Errorf("CachedRepository -> GetPasswordAndIDByUsername -> error: malformed cache entry")
+	}
+	copy(profileID[:], value)
+	return profileID, value[len(profileID):], nil
+}
+
+// GetRefreshTokenByID returns cached hash of the refresh token of the profile, missing profile is cached too
+func (c *CachedRepository) GetRefreshTokenByID(ctx context.Context, profileID uuid.UUID) (hashedRefresh []byte, err error) {
+	hashedRefresh, err = c.load(ctx, c.refreshKey(profileID), func(ctx context.Context) ([]byte, error) {
+		return c.ProfileRepository.GetRefreshTokenByID(ctx, profileID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CachedRepository -> GetRefreshTokenByID -> %w", err)
+	}
+	return hashedRefresh, nil
+}
+
+// CreateProfile creates the profile and drops cached absence of its username and id
+func (c *CachedRepository) CreateProfile(ctx context.Context, profile *model.Profile) error {
+	err := c.ProfileRepository.CreateProfile(ctx, profile)
+	if err != nil {
+		return fmt.Errorf("CachedRepository -> CreateProfile -> %w", err)
+	}
+	c.invalidate(ctx, c.usernameKey(profile.Username), c.refreshKey(profile.ID))
+	return nil
+}
+
+// AddRefreshToken replaces the refresh token and drops its cached hash, the entry is dropped even if the write failed
+// because the failure may come after commit
+func (c *CachedRepository) AddRefreshToken(ctx context.Context, refreshToken []byte, profileID uuid.UUID) error {
+	err := c.ProfileRepository.AddRefreshToken(ctx, refreshToken, profileID)
+	c.invalidate(ctx, c.refreshKey(profileID))
+	if err != nil {
+		return fmt.Errorf("CachedRepository -> AddRefreshToken -> %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile deletes the profile and drops its cached entries, username of the profile is read from the primary
+// before deletion because entries of logins are keyed by it and replicas may not have the profile yet
+func (c *CachedRepository) DeleteProfile(ctx context.Context, profileID uuid.UUID) error {
+	keys := []string{c.refreshKey(profileID)}
+	profile, lookupErr := c.ProfileRepository.GetProfileByID(primary.Pin(ctx), profileID)
+	if lookupErr == nil {
+		keys = append(keys, c.usernameKey(profile.Username))
+	}
+	err := c.ProfileRepository.DeleteProfile(ctx, profileID)
+	c.invalidate(ctx, keys...)
+	if err != nil {
+		return fmt.Errorf("CachedRepository -> DeleteProfile -> %w", err)
+	}
+	return nil
+}
+
+// load returns value of the entry or pgx.ErrNoRows if the row is cached as missing. On miss it calls fetch once
+// for all concurrent callers and caches its result, errors of the backend are logged and treated as misses.
+// fetch isn't canceled with context of the caller that started it, so the other callers still get the result,
+// and every caller stops waiting once its own context is done
+func (c *CachedRepository) load(ctx context.Context, key string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	entry, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		logrus.Errorf("CachedRepository -> load -> %v", err)
+	}
+	if !ok {
+		ch := c.group.DoChan(key, func() (interface{}, error) {
+			loadCtx, cancel := context.WithTimeout(detached{ctx}, c.opts.LoadTimeout)
+			defer cancel()
+			f := c.startFlight(key)
+			value, err := fetch(loadCtx)
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				c.store(loadCtx, key, []byte{notFound}, c.opts.NegativeTTL)
+			case err == nil:
+				c.store(loadCtx, key, append([]byte{found}, value...), c.opts.TTL)
+			}
+			if c.finishFlight(key, f) {
+				c.invalidate(loadCtx, key)
+			}
+			return value, err
+		})
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-ch:
+			if res.Err != nil {
+				return nil, res.Err
+			}
+			return res.Val.([]byte), nil
+		}
+	}
+
+	if len(entry) == 0 {
+		return nil, fmt.Errorf("load -> error: malformed cache entry")
+	}
+	if entry[0] == notFound {
+		return nil, pgx.ErrNoRows
+	}
+	return entry[1:], nil
+}
+
+// store saves the entry and logs the error of the backend, the read is answered anyway
+func (c *CachedRepository) store(ctx context.Context, key string, entry []byte, ttl time.Duration) {
+	if err := c.backend.Set(ctx, key, entry, ttl); err != nil {
+		logrus.Errorf("CachedRepository -> store -> %v", err)
+	}
+}
+
+// startFlight registers read that fills the entry of key
+func (c *CachedRepository) startFlight(key string) *flight {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f := &flight{}
+	c.loading[key] = f
+	return f
+}
+
+// finishFlight unregisters the read and reports whether the entry was invalidated while it was in flight
+func (c *CachedRepository) finishFlight(key string, f *flight) (stale bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.loading, key)
+	return f.stale
+}
+
+// invalidate drops the entries and marks reads filling them as stale, the error of the backend is only logged
+// because the write is already done and the entries expire after TTL anyway
+func (c *CachedRepository) invalidate(ctx context.Context, keys ...string) {
+	c.mu.Lock()
+	for _, key := range keys {
+		if f, ok := c.loading[key]; ok {
+			f.stale = true
+		}
+	}
+	c.mu.Unlock()
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		logrus.Errorf("CachedRepository -> invalidate -> %v", err)
+	}
+}
+
+func (c *CachedRepository) usernameKey(username string) string {
+	return c.opts.Prefix + "username:" + username
+}
+
+func (c *CachedRepository) refreshKey(profileID uuid.UUID) string {
+	return c.opts.Prefix + "refresh:" + profileID.String()
+}
+
+// detached keeps values of the parent context but isn't canceled with it
+type detached struct {
+	context.Context
+}
+
+// Deadline returns no deadline
+func (detached) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+// Done returns nil channel, the context is never canceled
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil because the context is never canceled
+func (detached) Err() error {
+	return nil
+}
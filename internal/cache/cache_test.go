@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{Prefix: "test:", TTL: time.Minute, NegativeTTL: time.Second, LoadTimeout: time.Second}
+
+func TestGetPasswordAndIDByUsernameCached(t *testing.T) {
+	profileID := uuid.New()
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Vladislav").Return(profileID, []byte("hash"), nil).Once()
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	for i := 0; i < 3; i++ {
+		id, password, err := c.GetPasswordAndIDByUsername(context.Background(), "Vladislav")
+		require.NoError(t, err)
+		require.Equal(t, profileID, id)
+		require.Equal(t, []byte("hash"), password)
+	}
+	r.AssertNumberOfCalls(t, "GetPasswordAndIDByUsername", 1)
+}
+
+func TestNegativeCaching(t *testing.T) {
+	profile := &model.Profile{ID: uuid.New(), Username: "Yaroslav"}
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).
+		Return(uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByUserName: %w", pgx.ErrNoRows)).Once()
+	r.On("CreateProfile", mock.Anything, profile).Return(nil)
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	for i := 0; i < 2; i++ {
+		_, _, err := c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+	r.AssertNumberOfCalls(t, "GetPasswordAndIDByUsername", 1)
+
+	require.NoError(t, c.CreateProfile(context.Background(), profile))
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).Return(profile.ID, []byte("hash"), nil).Once()
+	id, _, err := c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+}
+
+func TestRepositoryErrorsAreNotCached(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	profileID := uuid.New()
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Bronislav").Return(uuid.Nil, nil, errTimeout).Once()
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Bronislav").Return(profileID, []byte("hash"), nil).Once()
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	_, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Bronislav")
+	require.ErrorIs(t, err, errTimeout)
+	id, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Bronislav")
+	require.NoError(t, err)
+	require.Equal(t, profileID, id)
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	profile := &model.Profile{ID: uuid.New(), Username: "Rostislav"}
+	r := new(mocks.ProfileRepository)
+	r.On("GetRefreshTokenByID", mock.Anything, profile.ID).Return([]byte("old"), nil).Once()
+	r.On("GetRefreshTokenByID", mock.Anything, profile.ID).Return([]byte("new"), nil).Once()
+	r.On("AddRefreshToken", mock.Anything, []byte("new"), profile.ID).Return(nil)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).Return(profile.ID, []byte("hash"), nil).Once()
+	r.On("GetProfileByID", mock.Anything, profile.ID).Return(profile, nil)
+	r.On("DeleteProfile", mock.Anything, profile.ID).Return(nil)
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	for i := 0; i < 2; i++ {
+		refresh, err := c.GetRefreshTokenByID(context.Background(), profile.ID)
+		require.NoError(t, err)
+		require.Equal(t, []byte("old"), refresh)
+	}
+
+	require.NoError(t, c.AddRefreshToken(context.Background(), []byte("new"), profile.ID))
+	refresh, err := c.GetRefreshTokenByID(context.Background(), profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), refresh)
+
+	_, _, err = c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteProfile(context.Background(), profile.ID))
+
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).Return(uuid.Nil, nil, pgx.ErrNoRows).Once()
+	r.On("GetRefreshTokenByID", mock.Anything, profile.ID).Return(nil, pgx.ErrNoRows).Once()
+	_, _, err = c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = c.GetRefreshTokenByID(context.Background(), profile.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestWriteDuringLoadDropsLoadedEntry(t *testing.T) {
+	profile := &model.Profile{ID: uuid.New(), Username: "Stanislav"}
+	release := make(chan time.Time)
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).WaitUntil(release).
+		Return(uuid.Nil, nil, pgx.ErrNoRows).Once()
+	r.On("CreateProfile", mock.Anything, profile).Return(nil)
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	loaded := make(chan error, 1)
+	go func() {
+		_, _, err := c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+		loaded <- err
+	}()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.loading[c.usernameKey(profile.Username)] != nil
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.CreateProfile(context.Background(), profile))
+	close(release)
+	require.ErrorIs(t, <-loaded, pgx.ErrNoRows)
+
+	r.On("GetPasswordAndIDByUsername", mock.Anything, profile.Username).Return(profile.ID, []byte("hash"), nil).Once()
+	id, _, err := c.GetPasswordAndIDByUsername(context.Background(), profile.Username)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+}
+
+func TestConcurrentMissesCollapse(t *testing.T) {
+	profileID := uuid.New()
+	release := make(chan time.Time)
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Miroslav").WaitUntil(release).Return(profileID, []byte("hash"), nil)
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	const callers = 10
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			id, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Miroslav")
+			require.NoError(t, err)
+			require.Equal(t, profileID, id)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	r.AssertNumberOfCalls(t, "GetPasswordAndIDByUsername", 1)
+}
+
+func TestCanceledCallerDoesNotFailOthers(t *testing.T) {
+	profileID := uuid.New()
+	release := make(chan time.Time)
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Svyatoslav").WaitUntil(release).
+		Run(func(args mock.Arguments) {
+			require.NoError(t, args.Get(0).(context.Context).Err())
+		}).Return(profileID, []byte("hash"), nil)
+
+	c := NewCachedRepository(r, NewLRUBackend(10), testOptions)
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, _, err := c.GetPasswordAndIDByUsername(ctx, "Svyatoslav")
+		canceled <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	waited := make(chan error, 1)
+	go func() {
+		_, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Svyatoslav")
+		waited <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-canceled, context.Canceled)
+
+	close(release)
+	require.NoError(t, <-waited)
+	r.AssertNumberOfCalls(t, "GetPasswordAndIDByUsername", 1)
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/distuurbia/profile/internal/repository/memory"
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/distuurbia/profile/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestConformanceLRU(t *testing.T) {
+	repotest.Run(t, func(*testing.T) service.ProfileRepository {
+		return NewCachedRepository(memory.NewProfileRepository(), NewLRUBackend(100), testOptions)
+	})
+}
+
+func TestConformanceRedis(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) service.ProfileRepository {
+		server := miniredis.RunT(t)
+		backend := NewRedisBackend(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+		return NewCachedRepository(memory.NewProfileRepository(), backend, testOptions)
+	})
+}
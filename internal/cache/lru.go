@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// lruEntry is an element of LRUBackend list
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUBackend keeps up to size entries in memory of one replica and evicts the least recently used ones
+type LRUBackend struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewLRUBackend creates an object of *LRUBackend
+func NewLRUBackend(size int) *LRUBackend {
+	return &LRUBackend{size: size, order: list.New(), entries: make(map[string]*list.Element), now: time.Now}
+}
+
+// Get returns copy of the entry value, expired entry is dropped
+func (b *LRUBackend) Get(_ context.Context, key string) (value []byte, ok bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	element, ok := b.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !b.now().Before(entry.expiresAt) {
+		b.remove(element)
+		return nil, false, nil
+	}
+	b.order.MoveToFront(element)
+	return append([]byte(nil), entry.value...), true, nil
+}
+
+// Set saves copy of the value for ttl and evicts the least recently used entry when there is no room for it
+func (b *LRUBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entry := &lruEntry{key: key, value: append([]byte(nil), value...), expiresAt: b.now().Add(ttl)}
+	if element, ok := b.entries[key]; ok {
+		element.Value = entry
+		b.order.MoveToFront(element)
+		return nil
+	}
+	b.entries[key] = b.order.PushFront(entry)
+	if b.order.Len() > b.size {
+		b.remove(b.order.Back())
+	}
+	return nil
+}
+
+// Delete drops the entries
+func (b *LRUBackend) Delete(_ context.Context, keys ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, key := range keys {
+		if element, ok := b.entries[key]; ok {
+			b.remove(element)
+		}
+	}
+	return nil
+}
+
+// remove drops the element from the list and the map, it's called with mu locked
+func (b *LRUBackend) remove(element *list.Element) {
+	b.order.Remove(element)
+	delete(b.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUBackendEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	b := NewLRUBackend(2)
+	require.NoError(t, b.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, b.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := b.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, b.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = b.Get(ctx, "b")
+	require.False(t, ok)
+	value, ok, _ := b.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+
+	require.NoError(t, b.Delete(ctx, "a", "missing"))
+	_, ok, _ = b.Get(ctx, "a")
+	require.False(t, ok)
+}
+
+func TestLRUBackendExpires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	b := NewLRUBackend(2)
+	b.now = func() time.Time { return now }
+	require.NoError(t, b.Set(ctx, "a", []byte("1"), time.Second))
+
+	value, ok, _ := b.Get(ctx, "a")
+	require.True(t, ok)
+	value[0] = '9'
+	value, _, _ = b.Get(ctx, "a")
+	require.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Second)
+	_, ok, _ = b.Get(ctx, "a")
+	require.False(t, ok)
+	require.Empty(t, b.entries)
+}
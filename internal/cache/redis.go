@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend keeps entries in Redis shared by all replicas
+type RedisBackend struct {
+	client redis.UniversalClient
+}
+
+// NewRedisBackend creates an object of *RedisBackend
+func NewRedisBackend(client redis.UniversalClient) *RedisBackend {
+	return &RedisBackend{client: client}
+}
+
+// Get returns value of the entry, missing and expired entries aren't an error
+func (b *RedisBackend) Get(ctx context.Context, key string) (value []byte, ok bool, err error) {
+	value, err = b.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("RedisBackend -> Get: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set saves the value for ttl
+func (b *RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := b.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("RedisBackend -> Set: %w", err)
+	}
+	return nil
+}
+
+// Delete drops the entries
+func (b *RedisBackend) Delete(ctx context.Context, keys ...string) error {
+	if err := b.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("RedisBackend -> Delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisBackend(t *testing.T) {
+	server := miniredis.RunT(t)
+	b := NewRedisBackend(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	ctx := context.Background()
+
+	_, ok, err := b.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, b.Set(ctx, "a", []byte("1"), time.Second))
+	value, ok, err := b.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), value)
+
+	server.FastForward(time.Second)
+	_, ok, err = b.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, b.Set(ctx, "b", []byte("2"), time.Minute))
+	require.NoError(t, b.Delete(ctx, "b", "missing"))
+	require.False(t, server.Exists("b"))
+}
+
+func TestCachedRepositoryWithRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	b := NewRedisBackend(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	profileID := uuid.New()
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Vladislav").Return(profileID, []byte("hash"), nil).Once()
+
+	c := NewCachedRepository(r, b, testOptions)
+	for i := 0; i < 2; i++ {
+		id, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Vladislav")
+		require.NoError(t, err)
+		require.Equal(t, profileID, id)
+	}
+	require.True(t, server.Exists("test:username:Vladislav"))
+	require.Equal(t, time.Minute, server.TTL("test:username:Vladislav"))
+
+	server.SetError("LOADING")
+	r.On("GetPasswordAndIDByUsername", mock.Anything, "Vladislav").Return(profileID, []byte("hash"), nil).Once()
+	id, _, err := c.GetPasswordAndIDByUsername(context.Background(), "Vladislav")
+	require.NoError(t, err)
+	require.Equal(t, profileID, id)
+}
@@ -1,8 +1,91 @@
 // Package config represents struct Config.
 package config
 
+import "time"
+
 // Config is a structure of environment variables.
 type Config struct {
-	PostgresPath string `env:"POSTGRES_PATH"`
-	SecretKey    string `env:"SECRET_KEY"`
+	RepositoryBackend                string        `env:"REPOSITORY_BACKEND" envDefault:"postgres" validate:"oneof=postgres memory sqlite"`
+	PostgresPath                     string        `env:"POSTGRES_PATH"`
+	SQLitePath                       string        `env:"SQLITE_PATH" envDefault:"profile.db" validate:"required"`
+	PostgresMinConns                 int           `env:"POSTGRES_MIN_CONNS" envDefault:"0" validate:"gte=0,ltefield=PostgresMaxConns"`
+	PostgresMaxConns                 int           `env:"POSTGRES_MAX_CONNS" envDefault:"10" validate:"gt=0,lte=10000"`
+	PostgresMaxConnLifetime          time.Duration `env:"POSTGRES_MAX_CONN_LIFETIME" envDefault:"1h" validate:"gt=0"`
+	PostgresMaxConnIdleTime          time.Duration `env:"POSTGRES_MAX_CONN_IDLE_TIME" envDefault:"30m" validate:"gt=0"`
+	PostgresHealthCheckPeriod        time.Duration `env:"POSTGRES_HEALTH_CHECK_PERIOD" envDefault:"1m" validate:"gt=0"`
+	PostgresStatementTimeout         time.Duration `env:"POSTGRES_STATEMENT_TIMEOUT" envDefault:"30s" validate:"gte=0"`
+	PostgresReplicaPaths             []string      `env:"POSTGRES_REPLICA_PATHS" envSeparator:","`
+	PostgresReplicaMaxLag            time.Duration `env:"POSTGRES_REPLICA_MAX_LAG" envDefault:"5s" validate:"gt=0"`
+	PostgresReplicaCheckInterval     time.Duration `env:"POSTGRES_REPLICA_CHECK_INTERVAL" envDefault:"5s" validate:"gt=0"`
+	PostgresReplicaCheckTimeout      time.Duration `env:"POSTGRES_REPLICA_CHECK_TIMEOUT" envDefault:"2s" validate:"gt=0"`
+	SecretKey                        string        `env:"SECRET_KEY" validate:"required"`
+	EmailVerificationTTL             time.Duration `env:"EMAIL_VERIFICATION_TTL" envDefault:"24h" validate:"gt=0"`
+	PhoneVerificationTTL             time.Duration `env:"PHONE_VERIFICATION_TTL" envDefault:"10m" validate:"gt=0"`
+	PhoneVerificationAttempts        int           `env:"PHONE_VERIFICATION_ATTEMPTS" envDefault:"5" validate:"gt=0"`
+	LoginTokenTTL                    time.Duration `env:"LOGIN_TOKEN_TTL" envDefault:"15m" validate:"gt=0"`
+	WebAuthnRPID                     string        `env:"WEBAUTHN_RP_ID" envDefault:"localhost" validate:"required"`
+	WebAuthnRPDisplayName            string        `env:"WEBAUTHN_RP_DISPLAY_NAME" envDefault:"Profile" validate:"required"`
+	WebAuthnRPOrigins                []string      `env:"WEBAUTHN_RP_ORIGINS" envSeparator:"," envDefault:"http://localhost" validate:"required,dive,url"`
+	WebAuthnTimeout                  time.Duration `env:"WEBAUTHN_TIMEOUT" envDefault:"5m" validate:"gt=0"`
+	GRPCAddress                      string        `env:"GRPC_ADDRESS" envDefault:"localhost:8083" validate:"hostport"`
+	GRPCMaxRecvMsgSize               int           `env:"GRPC_MAX_RECV_MSG_SIZE" envDefault:"4194304" validate:"gt=0"`
+	GRPCMaxSendMsgSize               int           `env:"GRPC_MAX_SEND_MSG_SIZE" envDefault:"4194304" validate:"gt=0"`
+	GRPCMaxConcurrentStreams         int           `env:"GRPC_MAX_CONCURRENT_STREAMS" envDefault:"100" validate:"gt=0,lte=4294967295"`
+	GRPCMaxConnections               int           `env:"GRPC_MAX_CONNECTIONS" envDefault:"0" validate:"gte=0"`
+	GRPCKeepaliveTime                time.Duration `env:"GRPC_KEEPALIVE_TIME" envDefault:"2h" validate:"gt=0"`
+	GRPCKeepaliveTimeout             time.Duration `env:"GRPC_KEEPALIVE_TIMEOUT" envDefault:"20s" validate:"gt=0"`
+	GRPCKeepaliveMinTime             time.Duration `env:"GRPC_KEEPALIVE_MIN_TIME" envDefault:"5m" validate:"gte=0"`
+	GRPCKeepalivePermitWithoutStream bool          `env:"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM" envDefault:"false"`
+	GRPCMaxConnectionIdle            time.Duration `env:"GRPC_MAX_CONNECTION_IDLE" envDefault:"0s" validate:"gte=0"`
+	GRPCMaxConnectionAge             time.Duration `env:"GRPC_MAX_CONNECTION_AGE" envDefault:"0s" validate:"gte=0"`
+	GRPCMaxConnectionAgeGrace        time.Duration `env:"GRPC_MAX_CONNECTION_AGE_GRACE" envDefault:"0s" validate:"gte=0"`
+	JWTJWKSFile                      string        `env:"JWT_JWKS_FILE"`
+	JWTIssuer                        string        `env:"JWT_ISSUER"`
+	JWTAudience                      string        `env:"JWT_AUDIENCE"`
+	JWTLeeway                        time.Duration `env:"JWT_LEEWAY" envDefault:"30s" validate:"gte=0"`
+	RateLimitStore                   string        `env:"RATE_LIMIT_STORE" envDefault:"memory" validate:"oneof=memory postgres"`
+	RateLimits                       []string      `env:"RATE_LIMITS" envSeparator:"," envDefault:"/ProfileService/GetPasswordAndIDByUsername=1:5:target,/ProfileService/GetPasswordAndIDByEmail=1:5:target,/ProfileService/GetPasswordAndIDByPhone=1:5:target"`
+	RateLimitCleanupInterval         time.Duration `env:"RATE_LIMIT_CLEANUP_INTERVAL" envDefault:"1h" validate:"gt=0"`
+	RateLimitFailOpen                bool          `env:"RATE_LIMIT_FAIL_OPEN" envDefault:"true"`
+	TLSCertFile                      string        `env:"TLS_CERT_FILE" validate:"required_with=TLSKeyFile TLSClientCAFile"`
+	TLSKeyFile                       string        `env:"TLS_KEY_FILE" validate:"required_with=TLSCertFile"`
+	TLSClientCAFile                  string        `env:"TLS_CLIENT_CA_FILE" validate:"required_with=TLSAllowedClients"`
+	TLSAllowedClients                []string      `env:"TLS_ALLOWED_CLIENTS" envSeparator:","`
+	TLSReloadInterval                time.Duration `env:"TLS_RELOAD_INTERVAL" envDefault:"30s" validate:"gt=0"`
+	HTTPAddress                      string        `env:"HTTP_ADDRESS" validate:"hostport"`
+	MetricsAddress                   string        `env:"METRICS_ADDRESS" envDefault:"localhost:9090"`
+	HealthCheckInterval              time.Duration `env:"HEALTH_CHECK_INTERVAL" envDefault:"5s" validate:"gt=0"`
+	HealthCheckTimeout               time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s" validate:"gt=0"`
+	ShutdownTimeout                  time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s" validate:"gt=0"`
+	TracingExporter                  string        `env:"TRACING_EXPORTER" envDefault:"none" validate:"oneof=none stdout otlp"`
+	TracingOTLPEndpoint              string        `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317" validate:"hostport"`
+	TracingOTLPInsecure              bool          `env:"TRACING_OTLP_INSECURE" envDefault:"false"`
+	TracingSampleRatio               float64       `env:"TRACING_SAMPLE_RATIO" envDefault:"1" validate:"gte=0,lte=1"`
+	OutboxPublisher                  string        `env:"OUTBOX_PUBLISHER" envDefault:"none" validate:"oneof=none nats"`
+	OutboxBatchSize                  int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100" validate:"gt=0,lte=10000"`
+	OutboxPollInterval               time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s" validate:"gt=0"`
+	OutboxPublishTimeout             time.Duration `env:"OUTBOX_PUBLISH_TIMEOUT" envDefault:"5s" validate:"gt=0"`
+	OutboxRetention                  time.Duration `env:"OUTBOX_RETENTION" envDefault:"24h" validate:"gt=0"`
+	OutboxCleanupInterval            time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" envDefault:"1h" validate:"gt=0"`
+	NATSURL                          string        `env:"NATS_URL" envDefault:"nats://localhost:4222" validate:"required"`
+	NATSSubjectPrefix                string        `env:"NATS_SUBJECT_PREFIX" envDefault:"profile.events" validate:"required"`
+	WebhookBatchSize                 int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"20" validate:"gt=0,lte=1000"`
+	WebhookPollInterval              time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s" validate:"gt=0"`
+	WebhookTimeout                   time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s" validate:"gt=0"`
+	WebhookMinBackoff                time.Duration `env:"WEBHOOK_MIN_BACKOFF" envDefault:"10s" validate:"gt=0"`
+	WebhookMaxBackoff                time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"1h" validate:"gtefield=WebhookMinBackoff"`
+	WebhookMaxAttempts               int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"12" validate:"gt=0"`
+	WebhookRetention                 time.Duration `env:"WEBHOOK_RETENTION" envDefault:"168h" validate:"gt=0"`
+	WebhookCleanupInterval           time.Duration `env:"WEBHOOK_CLEANUP_INTERVAL" envDefault:"1h" validate:"gt=0"`
+	WatchBufferSize                  int           `env:"WATCH_BUFFER_SIZE" envDefault:"256" validate:"gt=0"`
+	WatchReconnectBackoff            time.Duration `env:"WATCH_RECONNECT_BACKOFF" envDefault:"1s" validate:"gt=0"`
+	ProfileChangesRetention          time.Duration `env:"PROFILE_CHANGES_RETENTION" envDefault:"24h" validate:"gt=0"`
+	ProfileChangesCleanupInterval    time.Duration `env:"PROFILE_CHANGES_CLEANUP_INTERVAL" envDefault:"1h" validate:"gt=0"`
+	CacheBackend                     string        `env:"CACHE_BACKEND" envDefault:"none" validate:"oneof=none memory redis"`
+	CacheSize                        int           `env:"CACHE_SIZE" envDefault:"10000" validate:"gt=0"`
+	CacheTTL                         time.Duration `env:"CACHE_TTL" envDefault:"1m" validate:"gt=0"`
+	CacheNegativeTTL                 time.Duration `env:"CACHE_NEGATIVE_TTL" envDefault:"10s" validate:"gt=0"`
+	CacheLoadTimeout                 time.Duration `env:"CACHE_LOAD_TIMEOUT" envDefault:"5s" validate:"gt=0"`
+	CacheKeyPrefix                   string        `env:"CACHE_KEY_PREFIX" envDefault:"profile:"`
+	RedisURL                         string        `env:"REDIS_URL" envDefault:"redis://localhost:6379/0" validate:"required"`
 }
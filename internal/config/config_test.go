@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setenv(t *testing.T, env map[string]string) {
+	for key, value := range env {
+		key, value := key, value
+		old, ok := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+		t.Cleanup(func() {
+			if ok {
+				_ = os.Setenv(key, old)
+				return
+			}
+			_ = os.Unsetenv(key)
+		})
+	}
+}
+
+func TestLoadAppliesDefaults(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "postgres://localhost/profile", "SECRET_KEY": "secret"})
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, "localhost:8083", cfg.GRPCAddress)
+	require.Equal(t, 10, cfg.PostgresMaxConns)
+	require.Equal(t, 4194304, cfg.GRPCMaxRecvMsgSize)
+}
+
+func TestLoadListsEveryInvalidField(t *testing.T) {
+	setenv(t, map[string]string{
+		"POSTGRES_PATH":        "",
+		"SECRET_KEY":           "secret",
+		"GRPC_ADDRESS":         "localhost",
+		"POSTGRES_MIN_CONNS":   "20",
+		"TRACING_EXPORTER":     "jaeger",
+		"TRACING_SAMPLE_RATIO": "2",
+		"WEBAUTHN_RP_ORIGINS":  "http://localhost,not a url",
+	})
+
+	_, err := Load()
+	require.Error(t, err)
+	for _, field := range []string{"POSTGRES_PATH", "GRPC_ADDRESS", "POSTGRES_MIN_CONNS", "TRACING_EXPORTER",
+		"TRACING_SAMPLE_RATIO", "WEBAUTHN_RP_ORIGINS[1]"} {
+		require.Contains(t, err.Error(), field)
+	}
+	require.NotContains(t, err.Error(), "SECRET_KEY")
+}
+
+func TestValidateGRPCAddress(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "postgres://localhost/profile", "SECRET_KEY": "secret"})
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	for address, valid := range map[string]bool{":8083": true, "0.0.0.0:8083": true, "[::1]:8083": true,
+		"localhost": false, "localhost:0": false, "localhost:65536": false, "localhost:http": false} {
+		cfg.GRPCAddress = address
+		require.Equal(t, valid, cfg.Validate() == nil, address)
+	}
+}
+
+func TestValidateTLSFiles(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "postgres://localhost/profile", "SECRET_KEY": "secret"})
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	cfg.TLSAllowedClients = []string{"*=auth.internal"}
+	err = cfg.Validate()
+	require.ErrorContains(t, err, "TLS_CLIENT_CA_FILE")
+
+	cfg.TLSClientCAFile = "ca.crt"
+	err = cfg.Validate()
+	require.ErrorContains(t, err, "TLS_CERT_FILE")
+	require.NotContains(t, err.Error(), "TLS_KEY_FILE")
+
+	cfg.TLSCertFile = "server.crt"
+	err = cfg.Validate()
+	require.ErrorContains(t, err, "TLS_KEY_FILE")
+
+	cfg.TLSKeyFile = "server.key"
+	require.NoError(t, cfg.Validate())
+}
+
+func TestValidateMemoryBackend(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "", "SECRET_KEY": "secret", "REPOSITORY_BACKEND": "memory"})
+	_, err := Load()
+	require.NoError(t, err)
+
+	setenv(t, map[string]string{"RATE_LIMIT_STORE": "postgres", "OUTBOX_PUBLISHER": "nats"})
+	_, err = Load()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "RATE_LIMIT_STORE must satisfy oneof=memory")
+	require.Contains(t, err.Error(), "OUTBOX_PUBLISHER must satisfy oneof=none")
+}
+
+func TestValidateSQLiteBackend(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "", "SECRET_KEY": "secret", "REPOSITORY_BACKEND": "sqlite"})
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, "profile.db", cfg.SQLitePath)
+
+	setenv(t, map[string]string{"OUTBOX_PUBLISHER": "nats"})
+	_, err = Load()
+	require.ErrorContains(t, err, "OUTBOX_PUBLISHER must satisfy oneof=none")
+}
+
+func TestLoadReplicaPaths(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "postgres://primary/profile", "SECRET_KEY": "secret",
+		"POSTGRES_REPLICA_PATHS": "postgres://replica1/profile,postgres://replica2/profile"})
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, []string{"postgres://replica1/profile", "postgres://replica2/profile"}, cfg.PostgresReplicaPaths)
+
+	setenv(t, map[string]string{"REPOSITORY_BACKEND": "memory"})
+	_, err = Load()
+	require.ErrorContains(t, err, "POSTGRES_REPLICA_PATHS")
+}
+
+func TestLoadHTTPAddressFallsBackToMetricsAddress(t *testing.T) {
+	setenv(t, map[string]string{"POSTGRES_PATH": "postgres://localhost/profile", "SECRET_KEY": "secret"})
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, "localhost:9090", cfg.HTTPAddress)
+
+	setenv(t, map[string]string{"METRICS_ADDRESS": ":9100"})
+	cfg, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, ":9100", cfg.HTTPAddress)
+
+	setenv(t, map[string]string{"HTTP_ADDRESS": ":9200"})
+	cfg, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, ":9200", cfg.HTTPAddress)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env"
+	"github.com/go-playground/validator"
+)
+
+// Load parses environment variables with defaults into Config and validates it. METRICS_ADDRESS is the previous name
+// of HTTP_ADDRESS, it's still used when HTTP_ADDRESS isn't set
+func Load() (*Config, error) {
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return nil, fmt.Errorf("Load -> %w", err)
+	}
+	if cfg.HTTPAddress == "" {
+		cfg.HTTPAddress = cfg.MetricsAddress
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("Load -> %w", err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks every field of Config and returns one error that names all invalid environment variables
+func (c *Config) Validate() error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("env")
+	})
+	if err := validate.RegisterValidation("hostport", isHostPort); err != nil {
+		return fmt.Errorf("Validate -> %w", err)
+	}
+	validate.RegisterStructValidation(validateBackend, Config{})
+
+	err := validate.Struct(c)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	invalid := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// values aren't printed because some of them are secrets
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		invalid = append(invalid, fmt.Sprintf("%s must satisfy %s", fieldErr.Field(), rule))
+	}
+	return fmt.Errorf("invalid config: %s", strings.Join(invalid, "; "))
+}
+
+// validateBackend checks settings that depend on the repository backend: Postgres needs its path, and memory and SQLite
+// backends have no replicas and no tables to keep rate limits in or to publish events from
+func validateBackend(sl validator.StructLevel) {
+	c := sl.Current().Interface().(Config)
+	if c.RepositoryBackend == "postgres" && c.PostgresPath == "" {
+		sl.ReportError(c.PostgresPath, "POSTGRES_PATH", "PostgresPath", "required", "")
+	}
+	if c.RepositoryBackend != "postgres" && len(c.PostgresReplicaPaths) > 0 {
+		sl.ReportError(c.PostgresReplicaPaths, "POSTGRES_REPLICA_PATHS", "PostgresReplicaPaths", "excluded_unless", "REPOSITORY_BACKEND postgres")
+	}
+	if c.RepositoryBackend != "postgres" && c.RateLimitStore == "postgres" {
+		sl.ReportError(c.RateLimitStore, "RATE_LIMIT_STORE", "RateLimitStore", "oneof", "memory")
+	}
+	if c.RepositoryBackend != "postgres" && c.OutboxPublisher != "none" {
+		sl.ReportError(c.OutboxPublisher, "OUTBOX_PUBLISHER", "OutboxPublisher", "oneof", "none")
+	}
+}
+
+// isHostPort checks that the field is host:port with valid port number, host may be empty to listen on all interfaces
+func isHostPort(fl validator.FieldLevel) bool {
+	_, port, err := net.SplitHostPort(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	return err == nil && n > 0
+}
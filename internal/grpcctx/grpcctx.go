@@ -0,0 +1,24 @@
+// Package grpcctx replaces context of gRPC streams, so stream interceptors can pass values to handlers like unary ones do
+package grpcctx
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// WrapServerStream returns ss whose Context returns ctx
+func WrapServerStream(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
+
+// serverStream overrides context of grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns context given to WrapServerStream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
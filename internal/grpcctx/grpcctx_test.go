@@ -0,0 +1,19 @@
+package grpcctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testKey struct{}
+
+func TestWrapServerStream(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testKey{}, "value")
+	var ss grpc.ServerStream
+
+	wrapped := WrapServerStream(ss, ctx)
+	require.Equal(t, "value", wrapped.Context().Value(testKey{}))
+}
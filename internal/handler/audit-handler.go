@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/model"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+)
+
+// ListAuditEvents validates filter from request and returns matching audit events from the newest to the oldest,
+// beforeId of the next page is id of the last returned event
+func (h *ProfileHandler) ListAuditEvents(ctx context.Context, req *protocol.ListAuditEventsRequest) (*protocol.ListAuditEventsResponse, error) {
+	filter := model.AuditFilter{Actor: req.Actor, Action: req.Action, BeforeID: req.BeforeId, Limit: req.Limit}
+	if req.TargetId != "" {
+		targetID, err := h.ValidationID(ctx, req.TargetId)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("ProfileHandler -> ListAuditEvents %v", err)
+			return &protocol.ListAuditEventsResponse{}, err
+		}
+		filter.TargetID = targetID
+	}
+	err := h.validate.StructCtx(ctx, &auditFilterRequest{
+		Actor: req.Actor, Action: req.Action, Since: req.Since, Until: req.Until, BeforeID: req.BeforeId, Limit: req.Limit,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListAuditEvents %v", err)
+		return &protocol.ListAuditEventsResponse{}, err
+	}
+	if req.Since != 0 {
+		filter.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until != 0 {
+		filter.Until = time.Unix(req.Until, 0)
+	}
+
+	events, err := h.s.ListAuditEvents(ctx, &filter)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListAuditEvents %v", err)
+		return &protocol.ListAuditEventsResponse{}, err
+	}
+	resp := &protocol.ListAuditEventsResponse{Events: make([]*protocol.AuditEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &protocol.AuditEvent{
+			Id:        event.ID,
+			Actor:     event.Actor,
+			Method:    event.Method,
+			Action:    event.Action,
+			TargetId:  event.TargetID.String(),
+			Changes:   string(event.Changes),
+			CreatedAt: event.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// auditFilterRequest contains validation rules of filter fields of ListAuditEventsRequest
+type auditFilterRequest struct {
+	Actor    string `validate:"max=255"`
+	Action   string `validate:"max=64"`
+	Since    int64  `validate:"gte=0"`
+	Until    int64  `validate:"gte=0"`
+	BeforeID int64  `validate:"gte=0"`
+	Limit    int32  `validate:"gte=0,lte=1000"`
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	"github.com/distuurbia/profile/internal/model"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuditEvents(t *testing.T) {
+	s := new(mocks.ProfileService)
+	createdAt := time.Now()
+	since := createdAt.Add(-time.Hour).Unix()
+	events := []*model.AuditEvent{{ID: 7, Actor: "sub:admin-console", Method: "/ProfileService/DeleteProfile",
+		Action: "DeleteProfile", TargetID: testProfile.ID, Changes: []byte(`{"username":"Vladimir"}`), CreatedAt: createdAt}}
+
+	s.On("ListAuditEvents", mock.Anything, &model.AuditFilter{TargetID: testProfile.ID, Action: "DeleteProfile",
+		Since: time.Unix(since, 0), Limit: 10}).Return(events, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ListAuditEvents(context.Background(), &protocol.ListAuditEventsRequest{
+		TargetId: testProfile.ID.String(), Action: "DeleteProfile", Since: since, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, int64(7), resp.Events[0].Id)
+	require.Equal(t, testProfile.ID.String(), resp.Events[0].TargetId)
+	require.Equal(t, `{"username":"Vladimir"}`, resp.Events[0].Changes)
+	require.Equal(t, createdAt.Unix(), resp.Events[0].CreatedAt)
+
+	_, err = h.ListAuditEvents(context.Background(), &protocol.ListAuditEventsRequest{TargetId: "not-uuid"})
+	require.Error(t, err)
+	_, err = h.ListAuditEvents(context.Background(), &protocol.ListAuditEventsRequest{Limit: 1001})
+	require.Error(t, err)
+	s.AssertNumberOfCalls(t, "ListAuditEvents", 1)
+}
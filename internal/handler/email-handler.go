@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/sirupsen/logrus"
+)
+
+// GetPasswordAndIDByEmail validates email from request and sends it lower to the service
+func (h *ProfileHandler) GetPasswordAndIDByEmail(ctx context.Context, req *protocol.GetPasswordAndIDByEmailRequest) (
+	*protocol.GetPasswordAndIDByEmailResponse, error) {
+	err := h.validate.VarCtx(ctx, req.Email, "required,email,max=254")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPasswordAndIDByEmail -> %v", err)
+		return &protocol.GetPasswordAndIDByEmailResponse{}, err
+	}
+
+	id, password, err := h.s.GetPasswordAndIDByEmail(ctx, req.Email)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPasswordAndIDByEmail -> %v", err)
+		return &protocol.GetPasswordAndIDByEmailResponse{}, err
+	}
+	return &protocol.GetPasswordAndIDByEmailResponse{Id: id.String(), Password: password}, nil
+}
+
+// UpdateEmail validates id and email from request and sends them lower to the service
+func (h *ProfileHandler) UpdateEmail(ctx context.Context, req *protocol.UpdateEmailRequest) (*protocol.UpdateEmailResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> UpdateEmail %v", err)
+		return &protocol.UpdateEmailResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Email, "required,email,max=254")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> UpdateEmail %v", err)
+		return &protocol.UpdateEmailResponse{}, err
+	}
+	err = h.s.UpdateEmail(ctx, profileID, req.Email)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> UpdateEmail -> %v", err)
+		return &protocol.UpdateEmailResponse{}, err
+	}
+	return &protocol.UpdateEmailResponse{}, nil
+}
+
+// IssueEmailVerificationToken validates id from request and returns token that confirms current email of the profile
+func (h *ProfileHandler) IssueEmailVerificationToken(ctx context.Context, req *protocol.IssueEmailVerificationTokenRequest) (
+	*protocol.IssueEmailVerificationTokenResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssueEmailVerificationToken %v", err)
+		return &protocol.IssueEmailVerificationTokenResponse{}, err
+	}
+	token, expiresAt, err := h.s.IssueEmailVerificationToken(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssueEmailVerificationToken %v", err)
+		return &protocol.IssueEmailVerificationTokenResponse{}, err
+	}
+	return &protocol.IssueEmailVerificationTokenResponse{Token: token, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// ConfirmEmail validates token from request and sends it lower to the service
+func (h *ProfileHandler) ConfirmEmail(ctx context.Context, req *protocol.ConfirmEmailRequest) (*protocol.ConfirmEmailResponse, error) {
+	err := h.validate.VarCtx(ctx, req.Token, "required,max=64")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConfirmEmail %v", err)
+		return &protocol.ConfirmEmailResponse{}, err
+	}
+	profileID, err := h.s.ConfirmEmail(ctx, req.Token)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConfirmEmail %v", err)
+		return &protocol.ConfirmEmailResponse{}, err
+	}
+	return &protocol.ConfirmEmailResponse{Id: profileID.String()}, nil
+}
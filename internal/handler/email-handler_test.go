@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPasswordAndIDByEmail(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("GetPasswordAndIDByEmail", mock.Anything, "vladimir@example.com").Return(testProfile.ID, []byte("pass"), nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.GetPasswordAndIDByEmail(context.Background(), &protocol.GetPasswordAndIDByEmailRequest{Email: "vladimir@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID.String(), resp.Id)
+
+	_, err = h.GetPasswordAndIDByEmail(context.Background(), &protocol.GetPasswordAndIDByEmailRequest{Email: "vladimir"})
+	require.Error(t, err)
+}
+
+func TestUpdateEmail(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("UpdateEmail", mock.Anything, testProfile.ID, "vladimir@example.com").Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.UpdateEmail(context.Background(), &protocol.UpdateEmailRequest{Id: testProfile.ID.String(), Email: "vladimir@example.com"})
+	require.NoError(t, err)
+}
+
+func TestIssueEmailVerificationToken(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	expiresAt := time.Now().Add(time.Hour)
+	s.On("IssueEmailVerificationToken", mock.Anything, testProfile.ID).Return("token", expiresAt, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.IssueEmailVerificationToken(context.Background(), &protocol.IssueEmailVerificationTokenRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, "token", resp.Token)
+	require.Equal(t, expiresAt.Unix(), resp.ExpiresAt)
+}
+
+func TestConfirmEmail(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("ConfirmEmail", mock.Anything, "token").Return(testProfile.ID, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ConfirmEmail(context.Background(), &protocol.ConfirmEmailRequest{Token: "token"})
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID.String(), resp.Id)
+}
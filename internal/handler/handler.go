@@ -4,8 +4,11 @@ package handler
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/distuurbia/profile/internal/logging"
 	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
 	protocol "github.com/distuurbia/profile/protocol/profile"
 	"github.com/go-playground/validator"
 	"github.com/google/uuid"
@@ -19,6 +22,35 @@ type ProfileService interface {
 	GetRefreshTokenByID(ctx context.Context, profileID uuid.UUID) (hashedRefresh []byte, err error)
 	AddRefreshToken(ctx context.Context, refreshToken []byte, profileID uuid.UUID) error
 	DeleteProfile(ctx context.Context, profileID uuid.UUID) error
+	GetProfileByID(ctx context.Context, profileID uuid.UUID) (*model.Profile, error)
+	RegenerateRecoveryCodes(ctx context.Context, profileID uuid.UUID) (recoveryCodes []string, err error)
+	ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, recoveryCode string) (codesLeft int32, err error)
+	GetPasswordAndIDByEmail(ctx context.Context, email string) (profileID uuid.UUID, password []byte, err error)
+	UpdateEmail(ctx context.Context, profileID uuid.UUID, email string) error
+	IssueEmailVerificationToken(ctx context.Context, profileID uuid.UUID) (token string, expiresAt time.Time, err error)
+	ConfirmEmail(ctx context.Context, token string) (profileID uuid.UUID, err error)
+	GetPasswordAndIDByPhone(ctx context.Context, phone string) (profileID uuid.UUID, password []byte, err error)
+	UpdatePhone(ctx context.Context, profileID uuid.UUID, phone string) error
+	IssuePhoneVerificationCode(ctx context.Context, profileID uuid.UUID) (code string, expiresAt time.Time, err error)
+	ConfirmPhone(ctx context.Context, profileID uuid.UUID, code string) error
+	IssueLoginToken(ctx context.Context, profileID uuid.UUID) (token string, expiresAt time.Time, err error)
+	RedeemLoginToken(ctx context.Context, token string) (profileID uuid.UUID, err error)
+	BeginWebAuthnRegistration(ctx context.Context, profileID uuid.UUID) (options []byte, err error)
+	FinishWebAuthnRegistration(ctx context.Context, profileID uuid.UUID, response []byte) (*model.WebAuthnCredential, []string, error)
+	BeginWebAuthnLogin(ctx context.Context, profileID uuid.UUID) (options []byte, err error)
+	FinishWebAuthnLogin(ctx context.Context, profileID uuid.UUID, response []byte) error
+	ListWebAuthnCredentials(ctx context.Context, profileID uuid.UUID) ([]*model.WebAuthnCredential, error)
+	DeleteWebAuthnCredential(ctx context.Context, profileID uuid.UUID, credentialID []byte) error
+	AssignRole(ctx context.Context, profileID uuid.UUID, role string) error
+	RevokeRole(ctx context.Context, profileID uuid.UUID, role string) error
+	GetEffectivePermissions(ctx context.Context, profileID uuid.UUID) (roles, permissions []string, err error)
+	CheckPermission(ctx context.Context, profileID uuid.UUID, permission string) (bool, error)
+	ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error)
+	CreateWebhookSubscription(ctx context.Context, url string, eventTypes []string) (*model.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	ListWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error)
+	WatchProfiles(ctx context.Context, fromSequence int64, ids []uuid.UUID, send func(*model.ProfileChange) error) error
 }
 
 // ProfileHandler is a structure of handler that contains an object implemented ProfileService interface and validator
@@ -37,7 +69,7 @@ func NewProfileHandler(s ProfileService, validate *validator.Validate) *ProfileH
 func (h *ProfileHandler) CreateProfile(ctx context.Context, req *protocol.CreateProfileRequest) (*protocol.CreateProfileResponse, error) {
 	parsedID, err := uuid.Parse(req.Profile.Id)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> CreateProfile -> %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CreateProfile -> %v", err)
 		return &protocol.CreateProfileResponse{}, err
 	}
 	var profile = model.Profile{
@@ -46,22 +78,24 @@ func (h *ProfileHandler) CreateProfile(ctx context.Context, req *protocol.Create
 		Country:  req.Profile.Country,
 		Username: req.Profile.Username,
 		Password: req.Profile.Password,
+		Email:    req.Profile.Email,
+		Phone:    req.Profile.Phone,
 	}
 	err = h.validate.StructCtx(ctx, profile)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> CreateProfile -> %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CreateProfile -> %v", err)
 		return &protocol.CreateProfileResponse{}, err
 	}
 	err = h.s.CreateProfile(ctx, &profile)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"Username":      profile.Username,
-			"Password":      profile.Password,
-			"Refresh Token": profile.RefreshToken,
-			"Age":           profile.Age,
-			"Country":       profile.Country,
-			"ID":            profile.ID,
-		}).Errorf("ProfileHandler -> CreateProfile -> %v", err)
+		logging.FromContext(ctx).WithFields(redact.Fields(logrus.Fields{
+			"Username": profile.Username,
+			"Email":    profile.Email,
+			"Phone":    profile.Phone,
+			"Age":      profile.Age,
+			"Country":  profile.Country,
+			"ID":       profile.ID,
+		})).Errorf("ProfileHandler -> CreateProfile -> %v", err)
 		return &protocol.CreateProfileResponse{}, err
 	}
 	return &protocol.CreateProfileResponse{}, nil
@@ -72,15 +106,15 @@ func (h *ProfileHandler) GetPasswordAndIDByUsername(ctx context.Context, req *pr
 	*protocol.GetPasswordAndIDByUsernameResponse, error) {
 	err := h.validate.VarCtx(ctx, req.Username, "required,min=4,max=20")
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> GetPasswordAndIDByUsername -> %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPasswordAndIDByUsername -> %v", err)
 		return &protocol.GetPasswordAndIDByUsernameResponse{}, err
 	}
 
 	id, password, err := h.s.GetPasswordAndIDByUsername(ctx, req.Username)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
+		logging.FromContext(ctx).WithFields(redact.Fields(logrus.Fields{
 			"Username": req.Username,
-		}).Errorf("ProfileHandler -> GetPasswordAndIDByUsername -> %v", err)
+		})).Errorf("ProfileHandler -> GetPasswordAndIDByUsername -> %v", err)
 		return &protocol.GetPasswordAndIDByUsernameResponse{}, nil
 	}
 	return &protocol.GetPasswordAndIDByUsernameResponse{Id: id.String(), Password: password}, nil
@@ -90,18 +124,18 @@ func (h *ProfileHandler) GetPasswordAndIDByUsername(ctx context.Context, req *pr
 func (h *ProfileHandler) ValidationID(ctx context.Context, id string) (uuid.UUID, error) {
 	err := h.validate.VarCtx(ctx, id, "required,uuid")
 	if err != nil {
-		logrus.Errorf("ValidationID -> %v", err)
+		logging.FromContext(ctx).Errorf("ValidationID -> %v", err)
 		return uuid.Nil, err
 	}
 
 	profileID, err := uuid.Parse(id)
 	if err != nil {
-		logrus.Errorf("ValidationID -> %v", err)
+		logging.FromContext(ctx).Errorf("ValidationID -> %v", err)
 		return uuid.Nil, err
 	}
 
 	if profileID == uuid.Nil {
-		logrus.Errorf("ValidationID -> error: failed to use uuid")
+		logging.FromContext(ctx).Errorf("ValidationID -> error: failed to use uuid")
 		return uuid.Nil, fmt.Errorf("ValidationID -> error: failed to use uuid")
 	}
 	return profileID, nil
@@ -112,12 +146,12 @@ func (h *ProfileHandler) GetRefreshTokenByID(ctx context.Context, req *protocol.
 	*protocol.GetRefreshTokenByIDResponse, error) {
 	profileID, err := h.ValidationID(ctx, req.Id)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> GetRefreshTokenByID %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetRefreshTokenByID %v", err)
 		return &protocol.GetRefreshTokenByIDResponse{}, err
 	}
 	hashedRefresh, err := h.s.GetRefreshTokenByID(ctx, profileID)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> GetRefreshTokenByID %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetRefreshTokenByID %v", err)
 		return &protocol.GetRefreshTokenByIDResponse{}, err
 	}
 	return &protocol.GetRefreshTokenByIDResponse{HashedRefresh: hashedRefresh}, nil
@@ -128,12 +162,12 @@ func (h *ProfileHandler) AddRefreshToken(ctx context.Context, req *protocol.AddR
 	*protocol.AddRefreshTokenResponse, error) {
 	profileID, err := h.ValidationID(ctx, req.Id)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> AddRefreshToken %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> AddRefreshToken %v", err)
 		return &protocol.AddRefreshTokenResponse{}, err
 	}
 	err = h.s.AddRefreshToken(ctx, req.HashedRefresh, profileID)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> AddRefreshToken %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> AddRefreshToken %v", err)
 		return &protocol.AddRefreshTokenResponse{}, err
 	}
 	return &protocol.AddRefreshTokenResponse{}, nil
@@ -143,15 +177,23 @@ func (h *ProfileHandler) AddRefreshToken(ctx context.Context, req *protocol.AddR
 func (h *ProfileHandler) DeleteProfile(ctx context.Context, req *protocol.DeleteProfileRequest) (*protocol.DeleteProfileResponse, error) {
 	profileID, err := h.ValidationID(ctx, req.Id)
 	if err != nil {
-		logrus.Errorf("ProfileHandler -> DeleteProfile %v", err)
+		logging.FromContext(ctx).Errorf("ProfileHandler -> DeleteProfile %v", err)
 		return &protocol.DeleteProfileResponse{}, err
 	}
 	err = h.s.DeleteProfile(ctx, profileID)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
+		logging.FromContext(ctx).WithFields(logrus.Fields{
 			"id": req.Id,
 		}).Errorf("ProfileHandler -> DeleteProfile -> %v", err)
 		return &protocol.DeleteProfileResponse{}, err
 	}
 	return &protocol.DeleteProfileResponse{}, nil
 }
+
+// unixOrZero converts optional time to unix seconds, zero means that time isn't set
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/distuurbia/profile/internal/handler/mocks"
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/redact"
 	protocol "github.com/distuurbia/profile/protocol/profile"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -82,3 +88,34 @@ func TestDeleteProfile(t *testing.T) {
 
 	require.NoError(t, err)
 }
+
+func TestCreateProfileDoesNotLogSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.AddHook(redact.NewHook())
+	ctx := logging.WithLogger(context.Background(), logrus.NewEntry(logger))
+
+	s := new(mocks.ProfileService)
+	s.On("CreateProfile", mock.Anything, mock.AnythingOfType("*model.Profile")).Return(errors.New("profile with such username already exists"))
+
+	h := NewProfileHandler(s, validate)
+
+	protoProfile := protocol.Profile{
+		Id:           uuid.New().String(),
+		Username:     testProfile.Username,
+		Password:     []byte("veryLongPlainPassword"),
+		RefreshToken: []byte("veryLongRefreshToken"),
+		Country:      testProfile.Country,
+		Age:          testProfile.Age,
+		Email:        "vladimir@example.com",
+	}
+	_, err := h.CreateProfile(ctx, &protocol.CreateProfileRequest{Profile: &protoProfile})
+	require.Error(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "CreateProfile")
+	for _, secret := range []string{"veryLongPlainPassword", "veryLongRefreshToken", "vladimir@example.com", fmt.Sprint(protoProfile.Password)} {
+		require.NotContains(t, out, secret)
+	}
+}
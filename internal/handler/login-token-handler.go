@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+)
+
+// IssueLoginToken validates id from request and returns single-use passwordless login token for the profile
+func (h *ProfileHandler) IssueLoginToken(ctx context.Context, req *protocol.IssueLoginTokenRequest) (*protocol.IssueLoginTokenResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssueLoginToken %v", err)
+		return &protocol.IssueLoginTokenResponse{}, err
+	}
+	token, expiresAt, err := h.s.IssueLoginToken(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssueLoginToken %v", err)
+		return &protocol.IssueLoginTokenResponse{}, err
+	}
+	return &protocol.IssueLoginTokenResponse{Token: token, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// RedeemLoginToken validates token from request and returns id of the profile it was issued for
+func (h *ProfileHandler) RedeemLoginToken(ctx context.Context, req *protocol.RedeemLoginTokenRequest) (*protocol.RedeemLoginTokenResponse, error) {
+	err := h.validate.VarCtx(ctx, req.Token, "required,max=64")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RedeemLoginToken %v", err)
+		return &protocol.RedeemLoginTokenResponse{}, err
+	}
+	profileID, err := h.s.RedeemLoginToken(ctx, req.Token)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RedeemLoginToken %v", err)
+		return &protocol.RedeemLoginTokenResponse{}, err
+	}
+	return &protocol.RedeemLoginTokenResponse{Id: profileID.String()}, nil
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueLoginToken(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	expiresAt := time.Now().Add(time.Minute)
+	s.On("IssueLoginToken", mock.Anything, testProfile.ID).Return("token", expiresAt, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.IssueLoginToken(context.Background(), &protocol.IssueLoginTokenRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, "token", resp.Token)
+	require.Equal(t, expiresAt.Unix(), resp.ExpiresAt)
+}
+
+func TestRedeemLoginToken(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("RedeemLoginToken", mock.Anything, "token").Return(testProfile.ID, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.RedeemLoginToken(context.Background(), &protocol.RedeemLoginTokenRequest{Token: "token"})
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID.String(), resp.Id)
+
+	_, err = h.RedeemLoginToken(context.Background(), &protocol.RedeemLoginTokenRequest{})
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ var (
 		Age:          27,
 	}
 	testProtoProfile = protocol.Profile{
+		Id:           testProfile.ID.String(),
 		Username:     testProfile.Username,
 		Password:     []byte("1234"),
 		RefreshToken: []byte("someToken"),
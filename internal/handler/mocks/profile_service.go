@@ -9,6 +9,8 @@ import (
 
 	model "github.com/distuurbia/profile/internal/model"
 
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -31,6 +33,160 @@ func (_m *ProfileService) AddRefreshToken(ctx context.Context, refreshToken []by
 	return r0
 }
 
+// AssignRole provides a mock function with given fields: ctx, profileID, role
+func (_m *ProfileService) AssignRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, profileID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BeginWebAuthnLogin provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) BeginWebAuthnLogin(ctx context.Context, profileID uuid.UUID) ([]byte, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]byte, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []byte); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginWebAuthnRegistration provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) BeginWebAuthnRegistration(ctx context.Context, profileID uuid.UUID) ([]byte, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]byte, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []byte); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckPermission provides a mock function with given fields: ctx, profileID, permission
+func (_m *ProfileService) CheckPermission(ctx context.Context, profileID uuid.UUID, permission string) (bool, error) {
+	ret := _m.Called(ctx, profileID, permission)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (bool, error)); ok {
+		return rf(ctx, profileID, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) bool); ok {
+		r0 = rf(ctx, profileID, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, profileID, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmEmail provides a mock function with given fields: ctx, token
+func (_m *ProfileService) ConfirmEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmPhone provides a mock function with given fields: ctx, profileID, code
+func (_m *ProfileService) ConfirmPhone(ctx context.Context, profileID uuid.UUID, code string) error {
+	ret := _m.Called(ctx, profileID, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: ctx, profileID, recoveryCode
+func (_m *ProfileService) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, recoveryCode string) (int32, error) {
+	ret := _m.Called(ctx, profileID, recoveryCode)
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (int32, error)); ok {
+		return rf(ctx, profileID, recoveryCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) int32); ok {
+		r0 = rf(ctx, profileID, recoveryCode)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, profileID, recoveryCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateProfile provides a mock function with given fields: ctx, profile
 func (_m *ProfileService) CreateProfile(ctx context.Context, profile *model.Profile) error {
 	ret := _m.Called(ctx, profile)
@@ -45,6 +201,32 @@ func (_m *ProfileService) CreateProfile(ctx context.Context, profile *model.Prof
 	return r0
 }
 
+// CreateWebhookSubscription provides a mock function with given fields: ctx, url, eventTypes
+func (_m *ProfileService) CreateWebhookSubscription(ctx context.Context, url string, eventTypes []string) (*model.WebhookSubscription, error) {
+	ret := _m.Called(ctx, url, eventTypes)
+
+	var r0 *model.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*model.WebhookSubscription, error)); ok {
+		return rf(ctx, url, eventTypes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *model.WebhookSubscription); ok {
+		r0 = rf(ctx, url, eventTypes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, url, eventTypes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteProfile provides a mock function with given fields: ctx, profileID
 func (_m *ProfileService) DeleteProfile(ctx context.Context, profileID uuid.UUID) error {
 	ret := _m.Called(ctx, profileID)
@@ -59,6 +241,188 @@ func (_m *ProfileService) DeleteProfile(ctx context.Context, profileID uuid.UUID
 	return r0
 }
 
+// DeleteWebAuthnCredential provides a mock function with given fields: ctx, profileID, credentialID
+func (_m *ProfileService) DeleteWebAuthnCredential(ctx context.Context, profileID uuid.UUID, credentialID []byte) error {
+	ret := _m.Called(ctx, profileID, credentialID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r0 = rf(ctx, profileID, credentialID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, subscriptionID
+func (_m *ProfileService) DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	ret := _m.Called(ctx, subscriptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishWebAuthnLogin provides a mock function with given fields: ctx, profileID, response
+func (_m *ProfileService) FinishWebAuthnLogin(ctx context.Context, profileID uuid.UUID, response []byte) error {
+	ret := _m.Called(ctx, profileID, response)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r0 = rf(ctx, profileID, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishWebAuthnRegistration provides a mock function with given fields: ctx, profileID, response
+func (_m *ProfileService) FinishWebAuthnRegistration(ctx context.Context, profileID uuid.UUID, response []byte) (*model.WebAuthnCredential, []string, error) {
+	ret := _m.Called(ctx, profileID, response)
+
+	var r0 *model.WebAuthnCredential
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) (*model.WebAuthnCredential, []string, error)); ok {
+		return rf(ctx, profileID, response)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) *model.WebAuthnCredential); ok {
+		r0 = rf(ctx, profileID, response)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []byte) []string); ok {
+		r1 = rf(ctx, profileID, response)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r2 = rf(ctx, profileID, response)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEffectivePermissions provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) GetEffectivePermissions(ctx context.Context, profileID uuid.UUID) ([]string, []string, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []string
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, []string, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) []string); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, profileID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPasswordAndIDByEmail provides a mock function with given fields: ctx, email
+func (_m *ProfileService) GetPasswordAndIDByEmail(ctx context.Context, email string) (uuid.UUID, []byte, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 uuid.UUID
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, []byte, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, email)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPasswordAndIDByPhone provides a mock function with given fields: ctx, phone
+func (_m *ProfileService) GetPasswordAndIDByPhone(ctx context.Context, phone string) (uuid.UUID, []byte, error) {
+	ret := _m.Called(ctx, phone)
+
+	var r0 uuid.UUID
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, []byte, error)); ok {
+		return rf(ctx, phone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, phone)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, phone)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, phone)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetPasswordAndIDByUsername provides a mock function with given fields: ctx, username
 func (_m *ProfileService) GetPasswordAndIDByUsername(ctx context.Context, username string) (uuid.UUID, []byte, error) {
 	ret := _m.Called(ctx, username)
@@ -94,6 +458,32 @@ func (_m *ProfileService) GetPasswordAndIDByUsername(ctx context.Context, userna
 	return r0, r1, r2
 }
 
+// GetProfileByID provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) GetProfileByID(ctx context.Context, profileID uuid.UUID) (*model.Profile, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 *model.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*model.Profile, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *model.Profile); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Profile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRefreshTokenByID provides a mock function with given fields: ctx, profileID
 func (_m *ProfileService) GetRefreshTokenByID(ctx context.Context, profileID uuid.UUID) ([]byte, error) {
 	ret := _m.Called(ctx, profileID)
@@ -120,6 +510,311 @@ func (_m *ProfileService) GetRefreshTokenByID(ctx context.Context, profileID uui
 	return r0, r1
 }
 
+// IssueEmailVerificationToken provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) IssueEmailVerificationToken(ctx context.Context, profileID uuid.UUID) (string, time.Time, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, time.Time, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) time.Time); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, profileID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IssueLoginToken provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) IssueLoginToken(ctx context.Context, profileID uuid.UUID) (string, time.Time, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, time.Time, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) time.Time); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, profileID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IssuePhoneVerificationCode provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) IssuePhoneVerificationCode(ctx context.Context, profileID uuid.UUID) (string, time.Time, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (string, time.Time, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) time.Time); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, profileID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, filter
+func (_m *ProfileService) ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*model.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter) ([]*model.AuditEvent, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter) []*model.AuditEvent); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebAuthnCredentials provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) ListWebAuthnCredentials(ctx context.Context, profileID uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []*model.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*model.WebAuthnCredential, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*model.WebAuthnCredential); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookAttempts provides a mock function with given fields: ctx, subscriptionID, limit
+func (_m *ProfileService) ListWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error) {
+	ret := _m.Called(ctx, subscriptionID, limit)
+
+	var r0 []*model.WebhookAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) ([]*model.WebhookAttempt, error)); ok {
+		return rf(ctx, subscriptionID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) []*model.WebhookAttempt); ok {
+		r0 = rf(ctx, subscriptionID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, subscriptionID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookSubscriptions provides a mock function with given fields: ctx
+func (_m *ProfileService) ListWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemLoginToken provides a mock function with given fields: ctx, token
+func (_m *ProfileService) RedeemLoginToken(ctx context.Context, token string) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, profileID
+func (_m *ProfileService) RegenerateRecoveryCodes(ctx context.Context, profileID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: ctx, profileID, role
+func (_m *ProfileService) RevokeRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, profileID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEmail provides a mock function with given fields: ctx, profileID, email
+func (_m *ProfileService) UpdateEmail(ctx context.Context, profileID uuid.UUID, email string) error {
+	ret := _m.Called(ctx, profileID, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePhone provides a mock function with given fields: ctx, profileID, phone
+func (_m *ProfileService) UpdatePhone(ctx context.Context, profileID uuid.UUID, phone string) error {
+	ret := _m.Called(ctx, profileID, phone)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchProfiles provides a mock function with given fields: ctx, fromSequence, ids, send
+func (_m *ProfileService) WatchProfiles(ctx context.Context, fromSequence int64, ids []uuid.UUID, send func(*model.ProfileChange) error) error {
+	ret := _m.Called(ctx, fromSequence, ids, send)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []uuid.UUID, func(*model.ProfileChange) error) error); ok {
+		r0 = rf(ctx, fromSequence, ids, send)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewProfileService creates a new instance of ProfileService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProfileService(t interface {
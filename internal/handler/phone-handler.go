@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/sirupsen/logrus"
+)
+
+// phoneValidationTag accepts only phones in E.164 format, e164 tag of validator alone lets country code start with zero
+const phoneValidationTag = "required,e164,excludes=+0"
+
+// GetPasswordAndIDByPhone validates phone from request and sends it lower to the service
+func (h *ProfileHandler) GetPasswordAndIDByPhone(ctx context.Context, req *protocol.GetPasswordAndIDByPhoneRequest) (
+	*protocol.GetPasswordAndIDByPhoneResponse, error) {
+	err := h.validate.VarCtx(ctx, req.Phone, phoneValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPasswordAndIDByPhone -> %v", err)
+		return &protocol.GetPasswordAndIDByPhoneResponse{}, err
+	}
+
+	id, password, err := h.s.GetPasswordAndIDByPhone(ctx, req.Phone)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPasswordAndIDByPhone -> %v", err)
+		return &protocol.GetPasswordAndIDByPhoneResponse{}, err
+	}
+	return &protocol.GetPasswordAndIDByPhoneResponse{Id: id.String(), Password: password}, nil
+}
+
+// UpdatePhone validates id and phone in E.164 format from request and sends them lower to the service
+func (h *ProfileHandler) UpdatePhone(ctx context.Context, req *protocol.UpdatePhoneRequest) (*protocol.UpdatePhoneResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> UpdatePhone %v", err)
+		return &protocol.UpdatePhoneResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Phone, phoneValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> UpdatePhone %v", err)
+		return &protocol.UpdatePhoneResponse{}, err
+	}
+	err = h.s.UpdatePhone(ctx, profileID, req.Phone)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> UpdatePhone -> %v", err)
+		return &protocol.UpdatePhoneResponse{}, err
+	}
+	return &protocol.UpdatePhoneResponse{}, nil
+}
+
+// IssuePhoneVerificationCode validates id from request and returns code that confirms current phone of the profile
+func (h *ProfileHandler) IssuePhoneVerificationCode(ctx context.Context, req *protocol.IssuePhoneVerificationCodeRequest) (
+	*protocol.IssuePhoneVerificationCodeResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssuePhoneVerificationCode %v", err)
+		return &protocol.IssuePhoneVerificationCodeResponse{}, err
+	}
+	code, expiresAt, err := h.s.IssuePhoneVerificationCode(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> IssuePhoneVerificationCode %v", err)
+		return &protocol.IssuePhoneVerificationCodeResponse{}, err
+	}
+	return &protocol.IssuePhoneVerificationCodeResponse{Code: code, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// ConfirmPhone validates id and numeric code from request and sends them lower to the service
+func (h *ProfileHandler) ConfirmPhone(ctx context.Context, req *protocol.ConfirmPhoneRequest) (*protocol.ConfirmPhoneResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConfirmPhone %v", err)
+		return &protocol.ConfirmPhoneResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Code, "required,numeric,max=10")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConfirmPhone %v", err)
+		return &protocol.ConfirmPhoneResponse{}, err
+	}
+	err = h.s.ConfirmPhone(ctx, profileID, req.Code)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> ConfirmPhone -> %v", err)
+		return &protocol.ConfirmPhoneResponse{}, err
+	}
+	return &protocol.ConfirmPhoneResponse{}, nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPasswordAndIDByPhone(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("GetPasswordAndIDByPhone", mock.Anything, "+375291234567").Return(testProfile.ID, []byte("pass"), nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.GetPasswordAndIDByPhone(context.Background(), &protocol.GetPasswordAndIDByPhoneRequest{Phone: "+375291234567"})
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID.String(), resp.Id)
+}
+
+func TestUpdatePhone(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("UpdatePhone", mock.Anything, testProfile.ID, "+375291234567").Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.UpdatePhone(context.Background(), &protocol.UpdatePhoneRequest{Id: testProfile.ID.String(), Phone: "+375291234567"})
+	require.NoError(t, err)
+
+	for _, phone := range []string{"375291234567", "+37529 123 45 67", "+0375291234567", ""} {
+		_, err = h.UpdatePhone(context.Background(), &protocol.UpdatePhoneRequest{Id: testProfile.ID.String(), Phone: phone})
+		require.Error(t, err, phone)
+	}
+	s.AssertNumberOfCalls(t, "UpdatePhone", 1)
+}
+
+func TestIssuePhoneVerificationCode(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	expiresAt := time.Now().Add(time.Minute)
+	s.On("IssuePhoneVerificationCode", mock.Anything, testProfile.ID).Return("123456", expiresAt, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.IssuePhoneVerificationCode(context.Background(), &protocol.IssuePhoneVerificationCodeRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, "123456", resp.Code)
+	require.Equal(t, expiresAt.Unix(), resp.ExpiresAt)
+}
+
+func TestConfirmPhone(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("ConfirmPhone", mock.Anything, testProfile.ID, "123456").Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.ConfirmPhone(context.Background(), &protocol.ConfirmPhoneRequest{Id: testProfile.ID.String(), Code: "123456"})
+	require.NoError(t, err)
+
+	_, err = h.ConfirmPhone(context.Background(), &protocol.ConfirmPhoneRequest{Id: testProfile.ID.String(), Code: "12a456"})
+	require.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"github.com/distuurbia/profile/internal/auth"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+)
+
+// fullMethod returns full gRPC method name of ProfileService
+func fullMethod(method string) string {
+	return "/" + protocol.ProfileService_ServiceDesc.ServiceName + "/" + method
+}
+
+// Policies returns access policies of ProfileService methods. Credential lookups and login flows are called
+// only by auth service, operations on a single profile are also allowed to the profile itself
+func Policies() auth.Policies {
+	service := auth.AnyOf(auth.RequireScope(auth.ScopeService), auth.RequireScope(auth.ScopeAdmin))
+	owner := auth.AnyOf(service, auth.SubjectIsID())
+
+	policies := auth.Policies{
+		fullMethod("DeleteProfile"):   auth.AnyOf(auth.RequireScope(auth.ScopeAdmin), auth.SubjectIsID()),
+		fullMethod("AssignRole"):      auth.RequireScope(auth.ScopeAdmin),
+		fullMethod("RevokeRole"):      auth.RequireScope(auth.ScopeAdmin),
+		fullMethod("CheckPermission"): service,
+		fullMethod("ListAuditEvents"): auth.RequireScope(auth.ScopeAdmin),
+		fullMethod("WatchProfiles"):   service,
+	}
+	for _, method := range []string{"CreateWebhookSubscription", "ListWebhookSubscriptions", "DeleteWebhookSubscription",
+		"ListWebhookAttempts"} {
+		policies[fullMethod(method)] = auth.RequireScope(auth.ScopeAdmin)
+	}
+	for _, method := range []string{"CreateProfile", "GetPasswordAndIDByUsername", "GetRefreshTokenByID", "AddRefreshToken",
+		"ConsumeRecoveryCode", "GetPasswordAndIDByEmail", "ConfirmEmail", "GetPasswordAndIDByPhone", "IssueLoginToken",
+		"RedeemLoginToken", "BeginWebAuthnLogin", "FinishWebAuthnLogin"} {
+		policies[fullMethod(method)] = service
+	}
+	for _, method := range []string{"GetProfileByID", "RegenerateRecoveryCodes", "UpdateEmail", "IssueEmailVerificationToken",
+		"UpdatePhone", "IssuePhoneVerificationCode", "ConfirmPhone", "BeginWebAuthnRegistration", "FinishWebAuthnRegistration",
+		"ListWebAuthnCredentials", "DeleteWebAuthnCredential", "GetPermissions"} {
+		policies[fullMethod(method)] = owner
+	}
+	return policies
+}
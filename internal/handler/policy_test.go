@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/auth"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoliciesCoverEveryMethod(t *testing.T) {
+	policies := Policies()
+	for _, method := range protocol.ProfileService_ServiceDesc.Methods {
+		require.Contains(t, policies, fullMethod(method.MethodName))
+	}
+}
+
+func TestDeleteProfilePolicy(t *testing.T) {
+	policy := Policies()[fullMethod("DeleteProfile")]
+	req := &protocol.DeleteProfileRequest{Id: testProfile.ID.String()}
+
+	require.True(t, policy(context.Background(), &auth.Principal{Subject: testProfile.ID.String()}, req))
+	require.True(t, policy(context.Background(), &auth.Principal{Subject: "someone", Scopes: []string{auth.ScopeAdmin}}, req))
+	require.False(t, policy(context.Background(), &auth.Principal{Subject: "someone"}, req))
+	require.False(t, policy(context.Background(), &auth.Principal{Subject: "auth", Scopes: []string{auth.ScopeService}}, req))
+	require.False(t, policy(context.Background(), nil, req))
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/sirupsen/logrus"
+)
+
+// GetProfileByID validates id from request and returns profile without its password and refresh token
+func (h *ProfileHandler) GetProfileByID(ctx context.Context, req *protocol.GetProfileByIDRequest) (*protocol.GetProfileByIDResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetProfileByID %v", err)
+		return &protocol.GetProfileByIDResponse{}, err
+	}
+	profile, err := h.s.GetProfileByID(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetProfileByID %v", err)
+		return &protocol.GetProfileByIDResponse{}, err
+	}
+	return &protocol.GetProfileByIDResponse{Profile: &protocol.Profile{
+		Id:                profile.ID.String(),
+		Username:          profile.Username,
+		Country:           profile.Country,
+		Age:               profile.Age,
+		RecoveryCodesLeft: profile.RecoveryCodesLeft,
+		Email:             profile.Email,
+		EmailVerifiedAt:   unixOrZero(profile.EmailVerifiedAt),
+		Phone:             profile.Phone,
+		PhoneVerifiedAt:   unixOrZero(profile.PhoneVerifiedAt),
+	}}, nil
+}
+
+// RegenerateRecoveryCodes validates id from request and returns new set of recovery codes for the profile
+func (h *ProfileHandler) RegenerateRecoveryCodes(ctx context.Context, req *protocol.RegenerateRecoveryCodesRequest) (
+	*protocol.RegenerateRecoveryCodesResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RegenerateRecoveryCodes %v", err)
+		return &protocol.RegenerateRecoveryCodesResponse{}, err
+	}
+	recoveryCodes, err := h.s.RegenerateRecoveryCodes(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RegenerateRecoveryCodes %v", err)
+		return &protocol.RegenerateRecoveryCodesResponse{}, err
+	}
+	return &protocol.RegenerateRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// ConsumeRecoveryCode validates id and recovery code from request and sends them lower to the service
+func (h *ProfileHandler) ConsumeRecoveryCode(ctx context.Context, req *protocol.ConsumeRecoveryCodeRequest) (
+	*protocol.ConsumeRecoveryCodeResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConsumeRecoveryCode %v", err)
+		return &protocol.ConsumeRecoveryCodeResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.RecoveryCode, "required,max=32")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ConsumeRecoveryCode %v", err)
+		return &protocol.ConsumeRecoveryCodeResponse{}, err
+	}
+	codesLeft, err := h.s.ConsumeRecoveryCode(ctx, profileID, req.RecoveryCode)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> ConsumeRecoveryCode -> %v", err)
+		return &protocol.ConsumeRecoveryCodeResponse{}, err
+	}
+	return &protocol.ConsumeRecoveryCodeResponse{RecoveryCodesLeft: codesLeft}, nil
+}
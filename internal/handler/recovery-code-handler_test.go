@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProfileByID(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("GetProfileByID", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return(&testProfile, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.GetProfileByID(context.Background(), &protocol.GetProfileByIDRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Username, resp.Profile.Username)
+	require.Empty(t, resp.Profile.Password)
+	require.Empty(t, resp.Profile.RefreshToken)
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("RegenerateRecoveryCodes", mock.Anything, mock.AnythingOfType("uuid.UUID")).Return([]string{"abcde-fghij"}, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.RegenerateRecoveryCodes(context.Background(), &protocol.RegenerateRecoveryCodesRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, []string{"abcde-fghij"}, resp.RecoveryCodes)
+}
+
+func TestConsumeRecoveryCode(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("ConsumeRecoveryCode", mock.Anything, mock.AnythingOfType("uuid.UUID"), "abcde-fghij").Return(int32(9), nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ConsumeRecoveryCode(context.Background(), &protocol.ConsumeRecoveryCodeRequest{
+		Id:           testProfile.ID.String(),
+		RecoveryCode: "abcde-fghij",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(9), resp.RecoveryCodesLeft)
+
+	_, err = h.ConsumeRecoveryCode(context.Background(), &protocol.ConsumeRecoveryCodeRequest{Id: testProfile.ID.String()})
+	require.Error(t, err)
+}
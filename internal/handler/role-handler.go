@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/service"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"google.golang.org/grpc"
+)
+
+const (
+	roleValidationTag       = "required,max=64"
+	permissionValidationTag = "required,max=128"
+)
+
+// PermissionCacheInterceptor gives every unary call its own cache of permissions, so repeated checks of
+// the same profile in one call read the database once
+func PermissionCacheInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(service.WithPermissionCache(ctx), req)
+	}
+}
+
+// AssignRole validates id and role from request and grants the role to the profile
+func (h *ProfileHandler) AssignRole(ctx context.Context, req *protocol.AssignRoleRequest) (*protocol.AssignRoleResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> AssignRole %v", err)
+		return &protocol.AssignRoleResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Role, roleValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> AssignRole %v", err)
+		return &protocol.AssignRoleResponse{}, err
+	}
+	err = h.s.AssignRole(ctx, profileID, req.Role)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> AssignRole %v", err)
+		return &protocol.AssignRoleResponse{}, err
+	}
+	return &protocol.AssignRoleResponse{}, nil
+}
+
+// RevokeRole validates id and role from request and takes the role away from the profile
+func (h *ProfileHandler) RevokeRole(ctx context.Context, req *protocol.RevokeRoleRequest) (*protocol.RevokeRoleResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RevokeRole %v", err)
+		return &protocol.RevokeRoleResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Role, roleValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RevokeRole %v", err)
+		return &protocol.RevokeRoleResponse{}, err
+	}
+	err = h.s.RevokeRole(ctx, profileID, req.Role)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> RevokeRole %v", err)
+		return &protocol.RevokeRoleResponse{}, err
+	}
+	return &protocol.RevokeRoleResponse{}, nil
+}
+
+// GetPermissions validates id from request and returns roles of the profile with permissions they grant
+func (h *ProfileHandler) GetPermissions(ctx context.Context, req *protocol.GetPermissionsRequest) (*protocol.GetPermissionsResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPermissions %v", err)
+		return &protocol.GetPermissionsResponse{}, err
+	}
+	roles, permissions, err := h.s.GetEffectivePermissions(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> GetPermissions %v", err)
+		return &protocol.GetPermissionsResponse{}, err
+	}
+	return &protocol.GetPermissionsResponse{Roles: roles, Permissions: permissions}, nil
+}
+
+// CheckPermission validates id and permission from request and answers whether the profile has the permission
+func (h *ProfileHandler) CheckPermission(ctx context.Context, req *protocol.CheckPermissionRequest) (*protocol.CheckPermissionResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CheckPermission %v", err)
+		return &protocol.CheckPermissionResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Permission, permissionValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CheckPermission %v", err)
+		return &protocol.CheckPermissionResponse{}, err
+	}
+	allowed, err := h.s.CheckPermission(ctx, profileID, req.Permission)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CheckPermission %v", err)
+		return &protocol.CheckPermissionResponse{}, err
+	}
+	return &protocol.CheckPermissionResponse{Allowed: allowed}, nil
+}
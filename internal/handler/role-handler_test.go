@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignRole(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("AssignRole", mock.Anything, testProfile.ID, "admin").Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.AssignRole(context.Background(), &protocol.AssignRoleRequest{Id: testProfile.ID.String(), Role: "admin"})
+	require.NoError(t, err)
+
+	_, err = h.AssignRole(context.Background(), &protocol.AssignRoleRequest{Id: testProfile.ID.String()})
+	require.Error(t, err)
+	s.AssertNumberOfCalls(t, "AssignRole", 1)
+}
+
+func TestRevokeRole(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("RevokeRole", mock.Anything, testProfile.ID, "admin").Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.RevokeRole(context.Background(), &protocol.RevokeRoleRequest{Id: testProfile.ID.String(), Role: "admin"})
+	require.NoError(t, err)
+}
+
+func TestGetPermissions(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("GetEffectivePermissions", mock.Anything, testProfile.ID).Return([]string{"user"}, []string{"profile.read"}, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.GetPermissions(context.Background(), &protocol.GetPermissionsRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, []string{"user"}, resp.Roles)
+	require.Equal(t, []string{"profile.read"}, resp.Permissions)
+}
+
+func TestCheckPermission(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("CheckPermission", mock.Anything, testProfile.ID, "profile.read").Return(true, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.CheckPermission(context.Background(), &protocol.CheckPermissionRequest{Id: testProfile.ID.String(),
+		Permission: "profile.read"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	_, err = h.CheckPermission(context.Background(), &protocol.CheckPermissionRequest{Id: "not uuid", Permission: "profile.read"})
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service"
+	"github.com/distuurbia/profile/internal/watch"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchedProfilesValidationTag limits the number of profiles watched by one stream
+const watchedProfilesValidationTag = "max=1000"
+
+// WatchProfiles validates filter from request and streams changes of the profiles until the client goes away.
+// OutOfRange means that changes after fromSequence aren't kept and profiles should be reloaded,
+// Unavailable means that the call can be retried with sequence of the last received change
+func (h *ProfileHandler) WatchProfiles(req *protocol.WatchProfilesRequest, stream protocol.ProfileService_WatchProfilesServer) error {
+	ctx := stream.Context()
+	err := h.validate.VarCtx(ctx, req.FromSequence, "gte=0")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> WatchProfiles %v", err)
+		return err
+	}
+	err = h.validate.VarCtx(ctx, req.Ids, watchedProfilesValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> WatchProfiles %v", err)
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(req.Ids))
+	for _, id := range req.Ids {
+		profileID, err := h.ValidationID(ctx, id)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("ProfileHandler -> WatchProfiles %v", err)
+			return err
+		}
+		ids = append(ids, profileID)
+	}
+
+	err = h.s.WatchProfiles(ctx, req.FromSequence, ids, func(change *model.ProfileChange) error {
+		return stream.Send(&protocol.WatchProfilesResponse{Change: &protocol.ProfileChange{
+			Sequence:  change.Sequence,
+			Id:        change.ProfileID.String(),
+			Operation: change.Operation,
+			ChangedAt: change.ChangedAt.Unix(),
+		}})
+	})
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> WatchProfiles %v", err)
+		switch {
+		case errors.Is(err, service.ErrChangesExpired):
+			return status.Error(codes.OutOfRange, service.ErrChangesExpired.Error())
+		case errors.Is(err, watch.ErrNotListening), errors.Is(err, watch.ErrClosed):
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return err
+	}
+	return nil
+}
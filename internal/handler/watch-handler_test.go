@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchStream collects responses sent to protocol.ProfileService_WatchProfilesServer
+type watchStream struct {
+	grpc.ServerStream
+	responses []*protocol.WatchProfilesResponse
+}
+
+func (s *watchStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *watchStream) Send(resp *protocol.WatchProfilesResponse) error {
+	s.responses = append(s.responses, resp)
+	return nil
+}
+
+func TestWatchProfiles(t *testing.T) {
+	s := new(mocks.ProfileService)
+	change := &model.ProfileChange{Sequence: 8, ProfileID: uuid.New(), Operation: "DELETE", ChangedAt: time.Now()}
+
+	s.On("WatchProfiles", mock.Anything, int64(7), []uuid.UUID{change.ProfileID}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args.Get(3).(func(*model.ProfileChange) error)
+			require.NoError(t, send(change))
+		}).Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	stream := &watchStream{}
+	err := h.WatchProfiles(&protocol.WatchProfilesRequest{FromSequence: 7, Ids: []string{change.ProfileID.String()}}, stream)
+	require.NoError(t, err)
+	require.Len(t, stream.responses, 1)
+	require.Equal(t, int64(8), stream.responses[0].Change.Sequence)
+	require.Equal(t, change.ProfileID.String(), stream.responses[0].Change.Id)
+	require.Equal(t, "DELETE", stream.responses[0].Change.Operation)
+
+	err = h.WatchProfiles(&protocol.WatchProfilesRequest{FromSequence: -1}, &watchStream{})
+	require.Error(t, err)
+	err = h.WatchProfiles(&protocol.WatchProfilesRequest{Ids: []string{"not an id"}}, &watchStream{})
+	require.Error(t, err)
+	s.AssertNumberOfCalls(t, "WatchProfiles", 1)
+}
+
+func TestWatchProfilesExpired(t *testing.T) {
+	s := new(mocks.ProfileService)
+	s.On("WatchProfiles", mock.Anything, int64(1), []uuid.UUID{}, mock.Anything).Return(service.ErrChangesExpired)
+
+	h := NewProfileHandler(s, validate)
+
+	err := h.WatchProfiles(&protocol.WatchProfilesRequest{FromSequence: 1}, &watchStream{})
+	require.Equal(t, codes.OutOfRange, status.Code(err))
+}
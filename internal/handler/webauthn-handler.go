@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/model"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/sirupsen/logrus"
+)
+
+// BeginWebAuthnRegistration validates id from request and returns options for registration of new passkey
+func (h *ProfileHandler) BeginWebAuthnRegistration(ctx context.Context, req *protocol.BeginWebAuthnRegistrationRequest) (
+	*protocol.BeginWebAuthnRegistrationResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> BeginWebAuthnRegistration %v", err)
+		return &protocol.BeginWebAuthnRegistrationResponse{}, err
+	}
+	options, err := h.s.BeginWebAuthnRegistration(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> BeginWebAuthnRegistration %v", err)
+		return &protocol.BeginWebAuthnRegistrationResponse{}, err
+	}
+	return &protocol.BeginWebAuthnRegistrationResponse{Options: options}, nil
+}
+
+// FinishWebAuthnRegistration validates id and attestation response from request and sends them lower to the service
+func (h *ProfileHandler) FinishWebAuthnRegistration(ctx context.Context, req *protocol.FinishWebAuthnRegistrationRequest) (
+	*protocol.FinishWebAuthnRegistrationResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> FinishWebAuthnRegistration %v", err)
+		return &protocol.FinishWebAuthnRegistrationResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Credential, "required")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> FinishWebAuthnRegistration %v", err)
+		return &protocol.FinishWebAuthnRegistrationResponse{}, err
+	}
+	credential, recoveryCodes, err := h.s.FinishWebAuthnRegistration(ctx, profileID, req.Credential)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> FinishWebAuthnRegistration -> %v", err)
+		return &protocol.FinishWebAuthnRegistrationResponse{}, err
+	}
+	return &protocol.FinishWebAuthnRegistrationResponse{Credential: protoWebAuthnCredential(credential), RecoveryCodes: recoveryCodes}, nil
+}
+
+// BeginWebAuthnLogin validates id from request and returns options for assertion with passkeys of the profile
+func (h *ProfileHandler) BeginWebAuthnLogin(ctx context.Context, req *protocol.BeginWebAuthnLoginRequest) (
+	*protocol.BeginWebAuthnLoginResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> BeginWebAuthnLogin %v", err)
+		return &protocol.BeginWebAuthnLoginResponse{}, err
+	}
+	options, err := h.s.BeginWebAuthnLogin(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> BeginWebAuthnLogin %v", err)
+		return &protocol.BeginWebAuthnLoginResponse{}, err
+	}
+	return &protocol.BeginWebAuthnLoginResponse{Options: options}, nil
+}
+
+// FinishWebAuthnLogin validates id and assertion response from request and sends them lower to the service
+func (h *ProfileHandler) FinishWebAuthnLogin(ctx context.Context, req *protocol.FinishWebAuthnLoginRequest) (
+	*protocol.FinishWebAuthnLoginResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> FinishWebAuthnLogin %v", err)
+		return &protocol.FinishWebAuthnLoginResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Assertion, "required")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> FinishWebAuthnLogin %v", err)
+		return &protocol.FinishWebAuthnLoginResponse{}, err
+	}
+	err = h.s.FinishWebAuthnLogin(ctx, profileID, req.Assertion)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> FinishWebAuthnLogin -> %v", err)
+		return &protocol.FinishWebAuthnLoginResponse{}, err
+	}
+	return &protocol.FinishWebAuthnLoginResponse{}, nil
+}
+
+// ListWebAuthnCredentials validates id from request and returns passkeys of the profile
+func (h *ProfileHandler) ListWebAuthnCredentials(ctx context.Context, req *protocol.ListWebAuthnCredentialsRequest) (
+	*protocol.ListWebAuthnCredentialsResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebAuthnCredentials %v", err)
+		return &protocol.ListWebAuthnCredentialsResponse{}, err
+	}
+	credentials, err := h.s.ListWebAuthnCredentials(ctx, profileID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebAuthnCredentials %v", err)
+		return &protocol.ListWebAuthnCredentialsResponse{}, err
+	}
+	resp := &protocol.ListWebAuthnCredentialsResponse{Credentials: make([]*protocol.WebAuthnCredential, 0, len(credentials))}
+	for _, credential := range credentials {
+		resp.Credentials = append(resp.Credentials, protoWebAuthnCredential(credential))
+	}
+	return resp, nil
+}
+
+// DeleteWebAuthnCredential deletes exact passkey of the profile using lower levels of microservice
+func (h *ProfileHandler) DeleteWebAuthnCredential(ctx context.Context, req *protocol.DeleteWebAuthnCredentialRequest) (
+	*protocol.DeleteWebAuthnCredentialResponse, error) {
+	profileID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> DeleteWebAuthnCredential %v", err)
+		return &protocol.DeleteWebAuthnCredentialResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.CredentialId, "required,max=1023")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> DeleteWebAuthnCredential %v", err)
+		return &protocol.DeleteWebAuthnCredentialResponse{}, err
+	}
+	err = h.s.DeleteWebAuthnCredential(ctx, profileID, req.CredentialId)
+	if err != nil {
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"id": req.Id,
+		}).Errorf("ProfileHandler -> DeleteWebAuthnCredential -> %v", err)
+		return &protocol.DeleteWebAuthnCredentialResponse{}, err
+	}
+	return &protocol.DeleteWebAuthnCredentialResponse{}, nil
+}
+
+// protoWebAuthnCredential converts model.WebAuthnCredential to its protocol message without public key
+func protoWebAuthnCredential(credential *model.WebAuthnCredential) *protocol.WebAuthnCredential {
+	return &protocol.WebAuthnCredential{
+		Id:              credential.ID,
+		SignCount:       credential.SignCount,
+		Transports:      credential.Transports,
+		Aaguid:          credential.AAGUID,
+		AttestationType: credential.AttestationType,
+		CreatedAt:       credential.CreatedAt.Unix(),
+		LastUsedAt:      unixOrZero(credential.LastUsedAt),
+	}
+}
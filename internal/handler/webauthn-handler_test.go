@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	"github.com/distuurbia/profile/internal/model"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCredential = model.WebAuthnCredential{
+	ID:              []byte("credential"),
+	PublicKey:       []byte("publicKey"),
+	SignCount:       3,
+	Transports:      []string{"internal"},
+	AAGUID:          make([]byte, 16),
+	AttestationType: "none",
+	CreatedAt:       time.Now(),
+}
+
+func TestBeginWebAuthnRegistration(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("BeginWebAuthnRegistration", mock.Anything, testProfile.ID).Return([]byte("{}"), nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.BeginWebAuthnRegistration(context.Background(), &protocol.BeginWebAuthnRegistrationRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), resp.Options)
+}
+
+func TestFinishWebAuthnRegistration(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("FinishWebAuthnRegistration", mock.Anything, testProfile.ID, []byte("{}")).Return(&testCredential, []string{"code"}, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.FinishWebAuthnRegistration(context.Background(), &protocol.FinishWebAuthnRegistrationRequest{
+		Id:         testProfile.ID.String(),
+		Credential: []byte("{}"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, testCredential.ID, resp.Credential.Id)
+	require.Equal(t, testCredential.SignCount, resp.Credential.SignCount)
+	require.Equal(t, testCredential.CreatedAt.Unix(), resp.Credential.CreatedAt)
+	require.Zero(t, resp.Credential.LastUsedAt)
+	require.Equal(t, []string{"code"}, resp.RecoveryCodes)
+}
+
+func TestFinishWebAuthnLogin(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("FinishWebAuthnLogin", mock.Anything, testProfile.ID, []byte("{}")).Return(nil)
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.FinishWebAuthnLogin(context.Background(), &protocol.FinishWebAuthnLoginRequest{Id: testProfile.ID.String(), Assertion: []byte("{}")})
+	require.NoError(t, err)
+
+	_, err = h.FinishWebAuthnLogin(context.Background(), &protocol.FinishWebAuthnLoginRequest{Id: testProfile.ID.String()})
+	require.Error(t, err)
+}
+
+func TestListWebAuthnCredentials(t *testing.T) {
+	s := new(mocks.ProfileService)
+
+	s.On("ListWebAuthnCredentials", mock.Anything, testProfile.ID).Return([]*model.WebAuthnCredential{&testCredential}, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ListWebAuthnCredentials(context.Background(), &protocol.ListWebAuthnCredentialsRequest{Id: testProfile.ID.String()})
+	require.NoError(t, err)
+	require.Len(t, resp.Credentials, 1)
+	require.Equal(t, testCredential.Transports, resp.Credentials[0].Transports)
+}
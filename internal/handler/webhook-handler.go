@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const eventTypesValidationTag = "required,max=3,unique,dive,oneof=" + model.EventProfileCreated + " " + model.EventProfileUpdated + " " +
+	model.EventProfileDeleted
+
+// CreateWebhookSubscription validates endpoint and event types from request and returns new subscription with its signing secret,
+// the secret isn't returned anywhere else
+func (h *ProfileHandler) CreateWebhookSubscription(ctx context.Context, req *protocol.CreateWebhookSubscriptionRequest) (
+	*protocol.CreateWebhookSubscriptionResponse, error) {
+	err := h.validate.VarCtx(ctx, req.Url, "required,url,max=2048")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CreateWebhookSubscription %v", err)
+		return &protocol.CreateWebhookSubscriptionResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.EventTypes, eventTypesValidationTag)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CreateWebhookSubscription %v", err)
+		return &protocol.CreateWebhookSubscriptionResponse{}, err
+	}
+	subscription, err := h.s.CreateWebhookSubscription(ctx, req.Url, req.EventTypes)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> CreateWebhookSubscription %v", err)
+		return &protocol.CreateWebhookSubscriptionResponse{}, webhookError(err)
+	}
+	return &protocol.CreateWebhookSubscriptionResponse{Subscription: protoWebhookSubscription(subscription), Secret: subscription.Secret}, nil
+}
+
+// ListWebhookSubscriptions returns all webhook subscriptions without their secrets
+func (h *ProfileHandler) ListWebhookSubscriptions(ctx context.Context, _ *protocol.ListWebhookSubscriptionsRequest) (
+	*protocol.ListWebhookSubscriptionsResponse, error) {
+	subscriptions, err := h.s.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebhookSubscriptions %v", err)
+		return &protocol.ListWebhookSubscriptionsResponse{}, webhookError(err)
+	}
+	resp := &protocol.ListWebhookSubscriptionsResponse{Subscriptions: make([]*protocol.WebhookSubscription, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, protoWebhookSubscription(subscription))
+	}
+	return resp, nil
+}
+
+// DeleteWebhookSubscription validates id from request and deletes the subscription with its pending deliveries
+func (h *ProfileHandler) DeleteWebhookSubscription(ctx context.Context, req *protocol.DeleteWebhookSubscriptionRequest) (
+	*protocol.DeleteWebhookSubscriptionResponse, error) {
+	subscriptionID, err := h.ValidationID(ctx, req.Id)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> DeleteWebhookSubscription %v", err)
+		return &protocol.DeleteWebhookSubscriptionResponse{}, err
+	}
+	err = h.s.DeleteWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> DeleteWebhookSubscription %v", err)
+		return &protocol.DeleteWebhookSubscriptionResponse{}, webhookError(err)
+	}
+	return &protocol.DeleteWebhookSubscriptionResponse{}, nil
+}
+
+// ListWebhookAttempts validates subscription id and limit from request and returns latest delivery attempts of the subscription
+func (h *ProfileHandler) ListWebhookAttempts(ctx context.Context, req *protocol.ListWebhookAttemptsRequest) (
+	*protocol.ListWebhookAttemptsResponse, error) {
+	subscriptionID, err := h.ValidationID(ctx, req.SubscriptionId)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebhookAttempts %v", err)
+		return &protocol.ListWebhookAttemptsResponse{}, err
+	}
+	err = h.validate.VarCtx(ctx, req.Limit, "gte=0,lte=1000")
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebhookAttempts %v", err)
+		return &protocol.ListWebhookAttemptsResponse{}, err
+	}
+	attempts, err := h.s.ListWebhookAttempts(ctx, subscriptionID, req.Limit)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("ProfileHandler -> ListWebhookAttempts %v", err)
+		return &protocol.ListWebhookAttemptsResponse{}, webhookError(err)
+	}
+	resp := &protocol.ListWebhookAttemptsResponse{Attempts: make([]*protocol.WebhookAttempt, 0, len(attempts))}
+	for _, attempt := range attempts {
+		resp.Attempts = append(resp.Attempts, &protocol.WebhookAttempt{
+			Id:          attempt.ID,
+			DeliveryId:  attempt.DeliveryID,
+			EventId:     attempt.EventID,
+			EventType:   attempt.EventType,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMs:  attempt.Duration.Milliseconds(),
+			AttemptedAt: attempt.AttemptedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+// protoWebhookSubscription converts model.WebhookSubscription to its protocol message without secret
+func protoWebhookSubscription(subscription *model.WebhookSubscription) *protocol.WebhookSubscription {
+	return &protocol.WebhookSubscription{
+		Id:         subscription.ID.String(),
+		Url:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt.Unix(),
+	}
+}
+
+// webhookError reports disabled webhooks as unimplemented, so clients can tell them from failures of the storage
+func webhookError(err error) error {
+	if errors.Is(err, service.ErrWebhooksDisabled) {
+		return status.Error(codes.Unimplemented, service.ErrWebhooksDisabled.Error())
+	}
+	return err
+}
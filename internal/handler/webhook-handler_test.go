@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/handler/mocks"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	s := new(mocks.ProfileService)
+	eventTypes := []string{model.EventProfileCreated, model.EventProfileDeleted}
+	subscription := &model.WebhookSubscription{ID: uuid.New(), URL: "https://partner.example.com/hooks", EventTypes: eventTypes,
+		Secret: "secret", CreatedAt: time.Now()}
+
+	s.On("CreateWebhookSubscription", mock.Anything, subscription.URL, eventTypes).Return(subscription, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.CreateWebhookSubscription(context.Background(), &protocol.CreateWebhookSubscriptionRequest{
+		Url: subscription.URL, EventTypes: eventTypes})
+	require.NoError(t, err)
+	require.Equal(t, subscription.ID.String(), resp.Subscription.Id)
+	require.Equal(t, "secret", resp.Secret)
+
+	_, err = h.CreateWebhookSubscription(context.Background(), &protocol.CreateWebhookSubscriptionRequest{
+		Url: subscription.URL, EventTypes: []string{"ProfileRenamed"}})
+	require.Error(t, err)
+	_, err = h.CreateWebhookSubscription(context.Background(), &protocol.CreateWebhookSubscriptionRequest{
+		Url: subscription.URL, EventTypes: []string{model.EventProfileCreated, model.EventProfileCreated}})
+	require.Error(t, err)
+	_, err = h.CreateWebhookSubscription(context.Background(), &protocol.CreateWebhookSubscriptionRequest{
+		Url: "not a url", EventTypes: eventTypes})
+	require.Error(t, err)
+	s.AssertNumberOfCalls(t, "CreateWebhookSubscription", 1)
+}
+
+func TestListWebhookSubscriptions(t *testing.T) {
+	s := new(mocks.ProfileService)
+	subscriptions := []*model.WebhookSubscription{{ID: uuid.New(), URL: "https://partner.example.com/hooks", Secret: "secret"}}
+
+	s.On("ListWebhookSubscriptions", mock.Anything).Return(subscriptions, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ListWebhookSubscriptions(context.Background(), &protocol.ListWebhookSubscriptionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Subscriptions, 1)
+	require.Equal(t, subscriptions[0].URL, resp.Subscriptions[0].Url)
+}
+
+func TestListWebhookAttempts(t *testing.T) {
+	s := new(mocks.ProfileService)
+	subscriptionID := uuid.New()
+	attempts := []*model.WebhookAttempt{{ID: 3, DeliveryID: 2, EventID: 1, StatusCode: 503, Error: "unexpected status",
+		Duration: 1500 * time.Millisecond, AttemptedAt: time.Now()}}
+
+	s.On("ListWebhookAttempts", mock.Anything, subscriptionID, int32(0)).Return(attempts, nil)
+
+	h := NewProfileHandler(s, validate)
+
+	resp, err := h.ListWebhookAttempts(context.Background(), &protocol.ListWebhookAttemptsRequest{SubscriptionId: subscriptionID.String()})
+	require.NoError(t, err)
+	require.Len(t, resp.Attempts, 1)
+	require.Equal(t, int32(503), resp.Attempts[0].StatusCode)
+	require.Equal(t, int64(1500), resp.Attempts[0].DurationMs)
+}
+
+func TestWebhooksDisabled(t *testing.T) {
+	s := new(mocks.ProfileService)
+	s.On("ListWebhookSubscriptions", mock.Anything).Return(nil, fmt.Errorf("ProfileService -> ListWebhookSubscriptions -> %w",
+		service.ErrWebhooksDisabled))
+
+	h := NewProfileHandler(s, validate)
+
+	_, err := h.ListWebhookSubscriptions(context.Background(), &protocol.ListWebhookSubscriptionsRequest{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,130 @@
+// Package health reports readiness of the service through grpc.health.v1 and HTTP probes
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger is an interface of the database whose availability makes the service ready
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Checker pings the database periodically and sets status of the services in grpc health server. Status is changed
+// under mutex, so a ping that finishes after Shutdown can't make the services ready again
+type Checker struct {
+	server   *health.Server
+	db       Pinger
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+	ready    atomic.Bool
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// NewChecker creates Checker of the given services, they are NOT_SERVING until the first successful ping
+func NewChecker(db Pinger, interval, timeout time.Duration, services ...string) *Checker {
+	c := &Checker{
+		server:   health.NewServer(),
+		db:       db,
+		services: append([]string{""}, services...),
+		interval: interval,
+		timeout:  timeout,
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// Server returns grpc health server to register in grpc.Server
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Run pings the database every interval until context is done
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check pings the database once and updates status of the services
+func (c *Checker) Check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	err := c.db.Ping(ctx)
+	if err != nil {
+		if c.ready.Load() {
+			logrus.Errorf("Checker -> Check -> %v", err)
+		}
+		c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+	c.setStatus(healthpb.HealthCheckResponse_SERVING)
+}
+
+// Shutdown makes all services NOT_SERVING for good, so load balancers stop sending new calls before server stops
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	c.ready.Store(false)
+	c.server.Shutdown()
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+	c.ready.Store(status == healthpb.HealthCheckResponse_SERVING)
+}
+
+// Ready reports whether the last ping succeeded and the service isn't shutting down
+func (c *Checker) Ready() bool {
+	return c.ready.Load()
+}
+
+// LivenessHandler answers /healthz with 200 while the process is able to serve HTTP
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeStatus(w, http.StatusOK)
+	})
+}
+
+// ReadinessHandler answers /readyz with 200 if the service is ready and 503 otherwise
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !c.Ready() {
+			writeStatus(w, http.StatusServiceUnavailable)
+			return
+		}
+		writeStatus(w, http.StatusOK)
+	})
+}
+
+func writeStatus(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(http.StatusText(code)))
+}
@@ -0,0 +1,114 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const testService = "ProfileService"
+
+// fakeDB fails ping while down is set
+type fakeDB struct {
+	down atomic.Bool
+}
+
+func (db *fakeDB) Ping(context.Context) error {
+	if db.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func status(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func probe(c *Checker, handler http.Handler) int {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	return rec.Code
+}
+
+func TestCheckerFollowsDatabase(t *testing.T) {
+	db := &fakeDB{}
+	c := NewChecker(db, time.Second, time.Second, testService)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, testService))
+	require.Equal(t, http.StatusServiceUnavailable, probe(c, c.ReadinessHandler()))
+
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, testService))
+	require.Equal(t, http.StatusOK, probe(c, c.ReadinessHandler()))
+
+	db.down.Store(true)
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, testService))
+	require.Equal(t, http.StatusServiceUnavailable, probe(c, c.ReadinessHandler()))
+	require.Equal(t, http.StatusOK, probe(c, c.LivenessHandler()))
+}
+
+func TestCheckerShutdown(t *testing.T) {
+	c := NewChecker(&fakeDB{}, time.Second, time.Second, testService)
+	c.Check(context.Background())
+
+	c.Shutdown()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, testService))
+	require.Equal(t, http.StatusServiceUnavailable, probe(c, c.ReadinessHandler()))
+
+	c.Check(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, testService))
+	require.False(t, c.Ready())
+}
+
+// slowDB blocks ping until release is closed
+type slowDB struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (db *slowDB) Ping(context.Context) error {
+	close(db.started)
+	<-db.release
+	return nil
+}
+
+func TestCheckerShutdownDuringPing(t *testing.T) {
+	db := &slowDB{started: make(chan struct{}), release: make(chan struct{})}
+	c := NewChecker(db, time.Second, time.Second, testService)
+	done := make(chan struct{})
+	go func() {
+		c.Check(context.Background())
+		close(done)
+	}()
+
+	<-db.started
+	c.Shutdown()
+	close(db.release)
+	<-done
+	require.False(t, c.Ready())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, testService))
+}
+
+func TestCheckerRun(t *testing.T) {
+	c := NewChecker(&fakeDB{}, time.Millisecond, time.Second, testService)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, c.Ready, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
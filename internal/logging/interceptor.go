@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"time"
+	"unicode"
+
+	"github.com/distuurbia/profile/internal/grpcctx"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is a metadata key of correlation id that is read from request and echoed in response headers
+const RequestIDKey = "x-request-id"
+
+const maxRequestIDLength = 128
+
+// UnaryServerInterceptor attaches request-scoped logger to the context of unary call and logs its result
+func UnaryServerInterceptor(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := requestIDFromContext(ctx)
+		entry := callLogger(ctx, logger, info.FullMethod, requestID)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID)); err != nil {
+			entry.Warnf("UnaryServerInterceptor -> SetHeader -> %v", err)
+		}
+
+		start := time.Now()
+		resp, err := handler(WithLogger(ctx, entry), req)
+		logCall(entry, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor attaches request-scoped logger to the context of stream and logs its result
+func StreamServerInterceptor(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		requestID := requestIDFromContext(ss.Context())
+		entry := callLogger(ss.Context(), logger, info.FullMethod, requestID)
+		if err := ss.SetHeader(metadata.Pairs(RequestIDKey, requestID)); err != nil {
+			entry.Warnf("StreamServerInterceptor -> SetHeader -> %v", err)
+		}
+
+		start := time.Now()
+		err := handler(srv, grpcctx.WrapServerStream(ss, WithLogger(ss.Context(), entry)))
+		logCall(entry, start, err)
+		return err
+	}
+}
+
+// requestIDFromContext returns request id from incoming metadata or generates new one if it's missing or malformed
+func requestIDFromContext(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 && validRequestID(values[0]) {
+		return values[0]
+	}
+	return uuid.NewString()
+}
+
+// validRequestID checks that request id from client is short and printable so it can't break log lines
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// callLogger creates logger with fields that describe the call
+func callLogger(ctx context.Context, logger *logrus.Logger, method, requestID string) *logrus.Entry {
+	fields := logrus.Fields{
+		"request_id": requestID,
+		"method":     method,
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = p.Addr.String()
+	}
+	return logger.WithFields(fields)
+}
+
+// logCall logs status code and duration of finished call with level depending on the code
+func logCall(entry *logrus.Entry, start time.Time, err error) {
+	code := status.Code(err)
+	entry = entry.WithFields(logrus.Fields{
+		"grpc_code": code.String(),
+		"duration":  time.Since(start).String(),
+	})
+	switch code {
+	case codes.OK:
+		entry.Info("finished call")
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		entry.Errorf("finished call -> %v", err)
+	default:
+		entry.Warnf("finished call -> %v", err)
+	}
+}
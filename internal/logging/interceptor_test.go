@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServer runs health server with logging interceptors over in-memory connection and returns its client,
+// the logger masks secrets like the standard logger of main does
+func startServer(t *testing.T) (healthpb.HealthClient, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(redact.NewHook())
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(logger)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(logger)),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return healthpb.NewHealthClient(conn), &buf
+}
+
+func lastEntry(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	return entry
+}
+
+func TestUnaryServerInterceptorEchoesRequestID(t *testing.T) {
+	client, buf := startServer(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDKey, "test-request-id")
+	var header metadata.MD
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"test-request-id"}, header.Get(RequestIDKey))
+
+	entry := lastEntry(t, buf)
+	require.Equal(t, "test-request-id", entry["request_id"])
+	require.Equal(t, "/grpc.health.v1.Health/Check", entry["method"])
+	require.Equal(t, "OK", entry["grpc_code"])
+	require.NotEmpty(t, entry["peer"])
+	require.NotEmpty(t, entry["duration"])
+}
+
+func TestUnaryServerInterceptorGeneratesRequestID(t *testing.T) {
+	client, buf := startServer(t)
+
+	var header metadata.MD
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"}, grpc.Header(&header))
+	require.Error(t, err)
+	require.Len(t, header.Get(RequestIDKey), 1)
+
+	entry := lastEntry(t, buf)
+	require.Equal(t, header.Get(RequestIDKey)[0], entry["request_id"])
+	require.Equal(t, "NotFound", entry["grpc_code"])
+	require.Equal(t, "warning", entry["level"])
+}
+
+func TestStreamServerInterceptorEchoesRequestID(t *testing.T) {
+	client, _ := startServer(t)
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), RequestIDKey, "stream-request-id"))
+	defer cancel()
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	header, err := stream.Header()
+	require.NoError(t, err)
+	require.Equal(t, []string{"stream-request-id"}, header.Get(RequestIDKey))
+}
+
+func TestFromContext(t *testing.T) {
+	require.NotNil(t, FromContext(context.Background()))
+
+	entry := logrus.NewEntry(logrus.New()).WithField("request_id", "id")
+	require.Equal(t, entry, FromContext(WithLogger(context.Background(), entry)))
+}
+
+func TestValidRequestID(t *testing.T) {
+	require.True(t, validRequestID("5b0c3d1e-id"))
+	require.False(t, validRequestID(""))
+	require.False(t, validRequestID("line\nbreak"))
+	require.False(t, validRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
@@ -0,0 +1,65 @@
+// Package metrics collects prometheus metrics of gRPC calls, postgres pool and repository queries
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "profile"
+
+// Metrics contains registry with all collectors of the service
+type Metrics struct {
+	registry *prometheus.Registry
+	handled  *prometheus.CounterVec
+	handling *prometheus.HistogramVec
+	queries  *prometheus.HistogramVec
+}
+
+// New creates registry with go runtime, process, gRPC and query collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_server",
+			Name:      "handled_total",
+			Help:      "Number of RPCs completed on the server by method and status code.",
+		}, []string{"method", "code"}),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_server",
+			Name:      "handling_seconds",
+			Help:      "Latency of RPCs handled by the server by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "query_seconds",
+			Help:      "Duration of repository queries by SQL statement and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"query", "status"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.handled,
+		m.handling,
+		m.queries,
+	)
+	return m
+}
+
+// Register adds custom collector to the registry of the service
+func (m *Metrics) Register(collector prometheus.Collector) error {
+	return m.registry.Register(collector)
+}
+
+// Handler returns http.Handler that exposes metrics in prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector reads pgxpool.Stat on every scrape
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired       *prometheus.Desc
+	idle           *prometheus.Desc
+	constructing   *prometheus.Desc
+	total          *prometheus.Desc
+	max            *prometheus.Desc
+	acquires       *prometheus.Desc
+	emptyAcquires  *prometheus.Desc
+	cancelAcquires *prometheus.Desc
+	acquireSeconds *prometheus.Desc
+}
+
+// NewPoolCollector creates collector of connection gauges and acquire counters of the pool.
+// pgxpool doesn't expose the number of goroutines waiting right now, so waiting is seen through
+// empty_acquire_total (acquires that had to wait for a connection) and acquire_seconds_total
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:           pool,
+		acquired:       desc("acquired_conns", "Number of currently acquired connections."),
+		idle:           desc("idle_conns", "Number of currently idle connections."),
+		constructing:   desc("constructing_conns", "Number of connections being established."),
+		total:          desc("total_conns", "Total number of connections in the pool."),
+		max:            desc("max_conns", "Maximum size of the pool."),
+		acquires:       desc("acquire_total", "Number of successful acquires from the pool."),
+		emptyAcquires:  desc("empty_acquire_total", "Number of acquires that waited for a connection because the pool was empty."),
+		cancelAcquires: desc("canceled_acquire_total", "Number of acquires canceled by context."),
+		acquireSeconds: desc("acquire_seconds_total", "Total time spent in successful acquires."),
+	}
+}
+
+// Describe sends descriptors of all pool metrics
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.constructing
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.cancelAcquires
+	ch <- c.acquireSeconds
+}
+
+// Collect sends current statistics of the pool
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructing, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.cancelAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryStartKey struct{}
+
+// queryStart keeps the statement and the time it was sent
+type queryStart struct {
+	sql   string
+	start time.Time
+}
+
+// queryTracer observes duration of every query sent through pgx connection
+type queryTracer struct {
+	m *Metrics
+}
+
+// QueryTracer returns pgx.QueryTracer that observes repository query durations, it's set to ConnConfig.Tracer
+func (m *Metrics) QueryTracer() pgx.QueryTracer {
+	return &queryTracer{m: m}
+}
+
+// TraceQueryStart remembers the statement and start time in the context of the query
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd observes duration of the query labeled by its statement and status
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	queryStatus := "ok"
+	if data.Err != nil {
+		queryStatus = "error"
+	}
+	t.m.queries.WithLabelValues(normalizeSQL(query.sql), queryStatus).Observe(time.Since(query.start).Seconds())
+}
+
+// normalizeSQL collapses whitespace of multiline statements so each of them is a single readable label
+func normalizeSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
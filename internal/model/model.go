@@ -1,14 +1,114 @@
 // Package model contains models of project
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Profile contains fields that we have in our postgresql table profiles
 type Profile struct {
-	Age          int32 `validate:"gte=18,lte=120"`
-	ID           uuid.UUID
-	Username     string `validate:"required,min=4,max=20"`
-	Country      string `validate:"required,min=2"`
-	Password     []byte `validate:"required,min=4"`
-	RefreshToken []byte
+	Age               int32 `validate:"gte=18,lte=120"`
+	ID                uuid.UUID
+	Username          string `validate:"required,min=4,max=20"`
+	Country           string `validate:"required,min=2"`
+	Password          []byte `validate:"required,min=4"`
+	RefreshToken      []byte
+	RecoveryCodesLeft int32
+	Email             string `validate:"omitempty,email,max=254"`
+	EmailVerifiedAt   *time.Time
+	Phone             string `validate:"omitempty,e164,excludes=+0"`
+	PhoneVerifiedAt   *time.Time
+}
+
+// WebAuthnCredential contains fields of passkey that we have in our postgresql table webauthn_credentials
+type WebAuthnCredential struct {
+	ID              []byte
+	ProfileID       uuid.UUID
+	PublicKey       []byte
+	SignCount       uint32
+	Transports      []string
+	AAGUID          []byte
+	AttestationType string
+	CreatedAt       time.Time
+	LastUsedAt      *time.Time
+}
+
+// AuditEvent contains fields of profile mutation that we have in our postgresql table audit_events
+type AuditEvent struct {
+	ID        int64
+	Actor     string
+	Method    string
+	Action    string
+	TargetID  uuid.UUID
+	Changes   []byte
+	CreatedAt time.Time
+}
+
+// AuditFilter narrows audit events, zero fields don't filter and events are returned from the newest to the oldest
+type AuditFilter struct {
+	TargetID uuid.UUID
+	Actor    string
+	Action   string
+	Since    time.Time
+	Until    time.Time
+	BeforeID int64
+	Limit    int32
+}
+
+// Types of profile lifecycle events published by the outbox relay
+const (
+	EventProfileCreated = "ProfileCreated"
+	EventProfileUpdated = "ProfileUpdated"
+	EventProfileDeleted = "ProfileDeleted"
+)
+
+// OutboxEvent contains fields of profile lifecycle event that we have in our postgresql table outbox_events
+type OutboxEvent struct {
+	ID        int64
+	Type      string
+	ProfileID uuid.UUID
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// WebhookSubscription contains fields of partner endpoint that we have in our postgresql table webhook_subscriptions
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery contains event that has to be sent to the endpoint of the subscription
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID uuid.UUID
+	URL            string
+	Secret         string
+	Event          OutboxEvent
+	Attempts       int32
+}
+
+// WebhookAttempt contains result of single request of the delivery, zero StatusCode means that no response was received
+type WebhookAttempt struct {
+	ID          int64
+	DeliveryID  int64
+	EventID     int64
+	EventType   string
+	StatusCode  int32
+	Error       string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+// ProfileChange contains fields of change of the profile that we have in our postgresql table profile_changes,
+// Operation is INSERT, UPDATE or DELETE
+type ProfileChange struct {
+	Sequence  int64
+	ProfileID uuid.UUID
+	Operation string
+	ChangedAt time.Time
 }
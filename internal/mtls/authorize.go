@@ -0,0 +1,103 @@
+package mtls
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// AnyMethod is a method of the rule that allows identity to call every RPC
+const AnyMethod = "*"
+
+// Rules maps full method name or AnyMethod to identities of clients allowed to call it
+type Rules map[string][]string
+
+// ParseRules parses rules of the form "<full method or *>=<identity>", identity is DNS or URI SAN or CN of client certificate
+func ParseRules(rules []string) (Rules, error) {
+	parsed := make(Rules)
+	for _, rule := range rules {
+		method, identity, ok := strings.Cut(rule, "=")
+		method, identity = strings.TrimSpace(method), strings.TrimSpace(identity)
+		if !ok || method == "" || identity == "" {
+			return nil, fmt.Errorf("ParseRules -> error: rule %q must look like <method>=<identity>", rule)
+		}
+		if method != AnyMethod && !strings.HasPrefix(method, "/") {
+			return nil, fmt.Errorf("ParseRules -> error: method of rule %q must be full method name like /ProfileService/Login", rule)
+		}
+		parsed[method] = append(parsed[method], identity)
+	}
+	return parsed, nil
+}
+
+// allowed checks that one of the identities is allowed to call the method
+func (r Rules) allowed(method string, identities []string) bool {
+	for _, key := range []string{method, AnyMethod} {
+		for _, allowedIdentity := range r[key] {
+			for _, identity := range identities {
+				if identity == allowedIdentity {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// authorize returns PermissionDenied if verified client certificate doesn't belong to identity allowed to call the method
+func (r Rules) authorize(ctx context.Context, method string) error {
+	if len(r) == 0 {
+		return nil
+	}
+	identities := clientIdentities(ctx)
+	if !r.allowed(method, identities) {
+		return status.Errorf(codes.PermissionDenied, "client %v isn't allowed to call %s", identities, method)
+	}
+	return nil
+}
+
+// clientIdentities returns DNS and URI SANs and CN of the verified client certificate
+func clientIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	identities := append([]string{}, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	return identities
+}
+
+// UnaryServerInterceptor rejects unary calls of clients that aren't allowed by rules, every client is allowed if rules are empty
+func UnaryServerInterceptor(rules Rules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rules.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams of clients that aren't allowed by rules, every client is allowed if rules are empty
+func StreamServerInterceptor(rules Rules) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rules.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
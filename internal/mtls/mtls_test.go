@@ -0,0 +1,209 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const checkMethod = "/grpc.health.v1.Health/Check"
+
+// testCA issues certificates for servers and clients of tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns PEM encoded certificate and key with the given common name and DNS SANs
+func (ca *testCA) issue(t *testing.T, serial int64, commonName string, dnsNames ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// startServer runs health server over TLS with certificates from dir and authorization rules
+func startServer(t *testing.T, dir string, rules Rules) (*Reloader, *bufconn.Listener) {
+	reloader, err := NewReloader(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(reloader.ServerConfig())),
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(rules)),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	return reloader, lis
+}
+
+// check calls the server with client certificate and returns error of the call and certificate presented by the server
+func check(t *testing.T, ca *testCA, lis *bufconn.Listener, clientCert *tls.Certificate) (*x509.Certificate, error) {
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	var serverCert *x509.Certificate
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    roots,
+		ServerName: "profile.internal",
+		VerifyConnection: func(state tls.ConnectionState) error {
+			serverCert = state.PeerCertificates[0]
+			return nil
+		},
+	}
+	if clientCert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return serverCert, err
+}
+
+func clientCertificate(t *testing.T, ca *testCA, commonName string, dnsNames ...string) *tls.Certificate {
+	certPEM, keyPEM := ca.issue(t, time.Now().UnixNano(), commonName, dnsNames...)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return &cert
+}
+
+func setupCerts(t *testing.T) (*testCA, string) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 2, "profile", "profile.internal")
+	writeFile(t, filepath.Join(dir, "ca.crt"), ca.pem)
+	writeFile(t, filepath.Join(dir, "server.crt"), certPEM)
+	writeFile(t, filepath.Join(dir, "server.key"), keyPEM)
+	return ca, dir
+}
+
+func TestMutualTLSAuthorization(t *testing.T) {
+	ca, dir := setupCerts(t)
+	rules, err := ParseRules([]string{checkMethod + "=auth.internal", "*=admin"})
+	require.NoError(t, err)
+	_, lis := startServer(t, dir, rules)
+
+	_, err = check(t, ca, lis, clientCertificate(t, ca, "auth", "auth.internal"))
+	require.NoError(t, err)
+	_, err = check(t, ca, lis, clientCertificate(t, ca, "admin"))
+	require.NoError(t, err)
+
+	_, err = check(t, ca, lis, clientCertificate(t, ca, "billing", "billing.internal"))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = check(t, ca, lis, nil)
+	require.Error(t, err)
+
+	otherCA := newTestCA(t)
+	_, err = check(t, ca, lis, clientCertificate(t, otherCA, "auth", "auth.internal"))
+	require.Error(t, err)
+}
+
+func TestReloaderPicksUpNewCertificate(t *testing.T) {
+	ca, dir := setupCerts(t)
+	reloader, lis := startServer(t, dir, nil)
+	client := clientCertificate(t, ca, "auth")
+
+	serverCert, err := check(t, ca, lis, client)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), serverCert.SerialNumber.Int64())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Run(ctx, 10*time.Millisecond)
+
+	writeFile(t, filepath.Join(dir, "server.key"), []byte("broken key"))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "server.key"), time.Now(), time.Now().Add(time.Minute)))
+	time.Sleep(50 * time.Millisecond)
+	serverCert, err = check(t, ca, lis, client)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), serverCert.SerialNumber.Int64())
+
+	certPEM, keyPEM := ca.issue(t, 3, "profile", "profile.internal")
+	writeFile(t, filepath.Join(dir, "server.crt"), certPEM)
+	writeFile(t, filepath.Join(dir, "server.key"), keyPEM)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "server.key"), time.Now(), time.Now().Add(2*time.Minute)))
+	require.Eventually(t, func() bool {
+		serverCert, err = check(t, ca, lis, client)
+		return err == nil && serverCert.SerialNumber.Int64() == 3
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"/ProfileService/Login=auth.internal", " * = admin", "*=spiffe://cluster/ns/auth"})
+	require.NoError(t, err)
+	require.True(t, rules.allowed("/ProfileService/Login", []string{"auth.internal"}))
+	require.False(t, rules.allowed("/ProfileService/DeleteProfile", []string{"auth.internal"}))
+	require.True(t, rules.allowed("/ProfileService/DeleteProfile", []string{"spiffe://cluster/ns/auth"}))
+
+	for _, rule := range []string{"auth.internal", "ProfileService/Login=auth", "/ProfileService/Login="} {
+		_, err = ParseRules([]string{rule})
+		require.Error(t, err, rule)
+	}
+}
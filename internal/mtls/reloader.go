@@ -0,0 +1,154 @@
+// Package mtls serves gRPC over TLS with optional client certificates, reloads certificates from disk
+// and authorizes calls by identity of the client certificate
+package mtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reloader keeps certificate of the server and CA bundle of clients loaded from files and reloads them when files change
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewReloader loads certificate, key and optional CA bundle of clients, clients aren't verified if clientCAFile is empty
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := r.Reload(); err != nil {
+		return nil, fmt.Errorf("NewReloader -> %w", err)
+	}
+	return r, nil
+}
+
+// Reload reads files again, previous certificates stay in use if any of the files is invalid
+func (r *Reloader) Reload() error {
+	modTimes, err := r.readModTimes()
+	if err != nil {
+		return fmt.Errorf("Reloader -> Reload -> %w", err)
+	}
+	cert, clientCA, err := r.load()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// invalid files are remembered as well, so they are read again only after the next change
+	r.modTimes = modTimes
+	if err != nil {
+		return fmt.Errorf("Reloader -> Reload -> %w", err)
+	}
+	r.cert = cert
+	r.clientCA = clientCA
+	return nil
+}
+
+func (r *Reloader) load() (*tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.clientCAFile == "" {
+		return &cert, nil, nil
+	}
+	pem, err := os.ReadFile(r.clientCAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientCA := x509.NewCertPool()
+	if !clientCA.AppendCertsFromPEM(pem) {
+		return nil, nil, fmt.Errorf("no certificates in %s", r.clientCAFile)
+	}
+	return &cert, clientCA, nil
+}
+
+// Run checks files every interval and reloads them after they were changed until context is done
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			logrus.Errorf("Reloader -> Run -> %v", err)
+			continue
+		}
+		logrus.Info("Reloader -> Run -> certificates reloaded")
+	}
+}
+
+// ServerConfig returns tls.Config that picks up current certificates on every handshake
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   tls.NoClientCert,
+			}
+			if r.clientCA != nil {
+				cfg.ClientCAs = r.clientCA
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) readModTimes() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, len(r.files()))
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// changed reports whether any of the files was modified after the last successful reload
+func (r *Reloader) changed() bool {
+	modTimes, err := r.readModTimes()
+	if err != nil {
+		logrus.Errorf("Reloader -> changed -> %v", err)
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
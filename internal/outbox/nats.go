@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes events to JetStream subject <prefix>.<event type>, the subjects must be captured by a stream
+type NATSPublisher struct {
+	js      nats.JetStreamContext
+	prefix  string
+	timeout time.Duration
+}
+
+// NewNATSPublisher creates an object of *NATSPublisher, timeout limits the wait for the stream to acknowledge each event
+func NewNATSPublisher(js nats.JetStreamContext, prefix string, timeout time.Duration) *NATSPublisher {
+	return &NATSPublisher{js: js, prefix: prefix, timeout: timeout}
+}
+
+// Publish sends envelope of the event with its id in Nats-Msg-Id header, so JetStream drops redelivered
+// duplicates, and waits until a stream acknowledges that it has stored the event
+func (p *NATSPublisher) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	data, err := Encode(event)
+	if err != nil {
+		return fmt.Errorf("NATSPublisher -> Publish -> %w", err)
+	}
+	msg := nats.NewMsg(p.prefix + "." + event.Type)
+	msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(event.ID, 10))
+	msg.Data = data
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	ack, err := p.js.PublishMsg(msg, nats.Context(ctx))
+	if err != nil {
+		return fmt.Errorf("NATSPublisher -> Publish -> %w", err)
+	}
+	if ack.Stream == "" {
+		return fmt.Errorf("NATSPublisher -> Publish -> error: event %d wasn't stored by any stream", event.ID)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Package natstest runs embedded NATS server for tests of packages that publish or subscribe to events
+package natstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// RunServer starts NATS server with JetStream on a random local port and shuts it down when the test ends,
+// URL of the server is returned by ClientURL
+func RunServer(t testing.TB) *server.Server {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true,
+		JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("natstest -> RunServer -> %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(10 * time.Second) {
+		srv.Shutdown()
+		t.Fatal("natstest -> RunServer -> server isn't ready for connections")
+	}
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+	return srv
+}
@@ -0,0 +1,80 @@
+// Package outbox relays profile lifecycle events saved by repository together with the profile to a message broker
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/poll"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher delivers event to subscribers, nil error means the broker has accepted the event
+type Publisher interface {
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// Store hands saved events to publish and forgets published ones, it's implemented by repository.OutboxRepository
+type Store interface {
+	Relay(ctx context.Context, limit int, publish func(context.Context, *model.OutboxEvent) error) (published int, err error)
+}
+
+// Envelope is the message that subscribers receive, ID is unique for every event and repeats
+// when the event is delivered twice
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	ProfileID  uuid.UUID       `json:"profileId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Encode returns JSON envelope of the event
+func Encode(event *model.OutboxEvent) ([]byte, error) {
+	data, err := json.Marshal(Envelope{
+		ID:         event.ID,
+		Type:       event.Type,
+		ProfileID:  event.ProfileID,
+		OccurredAt: event.CreatedAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Encode -> %w", err)
+	}
+	return data, nil
+}
+
+// Relay delivers events from Store to Publisher at least once
+type Relay struct {
+	store     Store
+	publisher Publisher
+	batchSize int
+	interval  time.Duration
+}
+
+// NewRelay creates an object of *Relay
+func NewRelay(store Store, publisher Publisher, batchSize int, interval time.Duration) *Relay {
+	return &Relay{store: store, publisher: publisher, batchSize: batchSize, interval: interval}
+}
+
+// Run relays events until context is done, full batches are relayed one after another
+// and otherwise the relay waits for the next poll
+func (r *Relay) Run(ctx context.Context) {
+	poll.Run(ctx, r.interval, r.relayBatch)
+}
+
+// relayBatch relays one batch and reports whether it was full, so more events may be waiting
+func (r *Relay) relayBatch(ctx context.Context) bool {
+	published, err := r.store.Relay(ctx, r.batchSize, r.publisher.Publish)
+	if err != nil {
+		if ctx.Err() == nil {
+			logrus.Errorf("Relay -> relayBatch -> %v", err)
+		}
+		return false
+	}
+	return published == r.batchSize
+}
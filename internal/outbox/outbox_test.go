@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/outbox/natstest"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps events in memory and behaves like repository.OutboxRepository
+type memoryStore struct {
+	mu     sync.Mutex
+	events []*model.OutboxEvent
+}
+
+func (s *memoryStore) Relay(ctx context.Context, limit int, publish func(context.Context, *model.OutboxEvent) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	published := 0
+	for published < limit && published < len(s.events) {
+		if err := publish(ctx, s.events[published]); err != nil {
+			s.events = s.events[published:]
+			return published, err
+		}
+		published++
+	}
+	s.events = s.events[published:]
+	return published, nil
+}
+
+func (s *memoryStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+// flakyPublisher fails every event the first time it's published
+type flakyPublisher struct {
+	mu        sync.Mutex
+	attempts  map[int64]int
+	delivered []int64
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, event *model.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts[event.ID]++
+	if p.attempts[event.ID] == 1 {
+		return errors.New("broker is unavailable")
+	}
+	p.delivered = append(p.delivered, event.ID)
+	return nil
+}
+
+func (p *flakyPublisher) deliveredIDs() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int64(nil), p.delivered...)
+}
+
+func testEvent(id int64, eventType string) *model.OutboxEvent {
+	return &model.OutboxEvent{ID: id, Type: eventType, ProfileID: uuid.New(), Payload: []byte(`{"username":"Vladimir"}`),
+		CreatedAt: time.Now().UTC().Truncate(time.Second)}
+}
+
+func TestRelayRetriesFailedEvents(t *testing.T) {
+	store := &memoryStore{events: []*model.OutboxEvent{
+		testEvent(1, model.EventProfileCreated), testEvent(2, model.EventProfileUpdated), testEvent(3, model.EventProfileDeleted),
+	}}
+	publisher := &flakyPublisher{attempts: map[int64]int{}}
+	relay := NewRelay(store, publisher, 2, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return store.len() == 0 }, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	require.Equal(t, []int64{1, 2, 3}, publisher.deliveredIDs())
+}
+
+func TestNATSPublisher(t *testing.T) {
+	srv := natstest.RunServer(t)
+	conn, err := nats.Connect(srv.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+	_, err = js.AddStream(&nats.StreamConfig{Name: "PROFILE_EVENTS", Subjects: []string{"profile.events.>"}})
+	require.NoError(t, err)
+
+	event := testEvent(42, model.EventProfileCreated)
+	publisher := NewNATSPublisher(js, "profile.events", time.Second)
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	require.NoError(t, publisher.Publish(context.Background(), event))
+	info, err := js.StreamInfo("PROFILE_EVENTS")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), info.State.Msgs)
+
+	err = NewNATSPublisher(js, "other.events", time.Second).Publish(context.Background(), event)
+	require.Error(t, err)
+
+	msg, err := js.GetLastMsg("PROFILE_EVENTS", "profile.events.ProfileCreated")
+	require.NoError(t, err)
+	require.Equal(t, "profile.events.ProfileCreated", msg.Subject)
+	require.Equal(t, "42", msg.Header.Get(nats.MsgIdHdr))
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(msg.Data, &envelope))
+	require.Equal(t, event.ID, envelope.ID)
+	require.Equal(t, event.Type, envelope.Type)
+	require.Equal(t, event.ProfileID, envelope.ProfileID)
+	require.True(t, event.CreatedAt.Equal(envelope.OccurredAt))
+	require.JSONEq(t, string(event.Payload), string(envelope.Data))
+}
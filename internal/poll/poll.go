@@ -0,0 +1,23 @@
+// Package poll runs background workers that process batches from a table on an interval
+package poll
+
+import (
+	"context"
+	"time"
+)
+
+// Run calls fn right away and then every interval until context is done. While fn reports that more work is waiting,
+// it's called again without waiting for the next tick, so backlogs are drained one batch after another
+func Run(ctx context.Context, interval time.Duration, fn func(ctx context.Context) (more bool)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for fn(ctx) && ctx.Err() == nil {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDrainsBacklog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	backlog := 3
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, time.Hour, func(context.Context) bool {
+			calls++
+			if backlog == 0 {
+				cancel()
+				return false
+			}
+			backlog--
+			return true
+		})
+		close(done)
+	}()
+
+	<-done
+	require.Equal(t, 4, calls)
+}
+
+func TestRunPollsEveryInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	polls := make(chan struct{}, 3)
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, time.Millisecond, func(context.Context) bool {
+			select {
+			case polls <- struct{}{}:
+			default:
+			}
+			return false
+		})
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		<-polls
+	}
+	cancel()
+	<-done
+}
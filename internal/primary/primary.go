@@ -0,0 +1,19 @@
+// Package primary marks contexts whose reads must go to the primary database. Services pin reads that follow
+// their own writes, so replicas lagging behind the primary can't answer them with stale or missing rows
+package primary
+
+import "context"
+
+// pinKey marks context of calls that must read from the primary
+type pinKey struct{}
+
+// Pin returns context whose reads go to the primary even if they could be served by replicas
+func Pin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinKey{}, true)
+}
+
+// Pinned reports whether reads of the context must go to the primary
+func Pinned(ctx context.Context) bool {
+	pinned, _ := ctx.Value(pinKey{}).(bool)
+	return pinned
+}
@@ -0,0 +1,18 @@
+package primary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPin(t *testing.T) {
+	ctx := context.Background()
+	require.False(t, Pinned(ctx))
+	pinned := Pin(ctx)
+	require.True(t, Pinned(pinned))
+	child, cancel := context.WithCancel(pinned)
+	defer cancel()
+	require.True(t, Pinned(child))
+}
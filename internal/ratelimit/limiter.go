@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/distuurbia/profile/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfterKey is a metadata key with number of seconds after which throttled call can be retried
+const RetryAfterKey = "retry-after"
+
+// AnyMethod is a method of the policy that applies to every RPC without its own policy
+const AnyMethod = "*"
+
+// Policy is a token bucket of calls of one method by one caller, ByTarget makes separate bucket for every
+// username, email or phone from request
+type Policy struct {
+	Rate     float64
+	Burst    int
+	ByTarget bool
+}
+
+// Policies maps full method names or AnyMethod to policies
+type Policies map[string]Policy
+
+// ParsePolicies parses policies of the form "<full method or *>=<tokens per second>:<burst>[:target]"
+func ParsePolicies(policies []string) (Policies, error) {
+	parsed := make(Policies)
+	for _, policy := range policies {
+		method, limits, ok := strings.Cut(policy, "=")
+		method = strings.TrimSpace(method)
+		parts := strings.Split(strings.TrimSpace(limits), ":")
+		if !ok || method == "" || len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("ParsePolicies -> error: policy %q must look like <method>=<rate>:<burst>[:target]", policy)
+		}
+		rate, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("ParsePolicies -> error: rate of policy %q must be positive number", policy)
+		}
+		burst, err := strconv.Atoi(parts[1])
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("ParsePolicies -> error: burst of policy %q must be positive integer", policy)
+		}
+		byTarget := len(parts) == 3
+		if byTarget && parts[2] != "target" {
+			return nil, fmt.Errorf("ParsePolicies -> error: unknown option %q of policy %q", parts[2], policy)
+		}
+		parsed[method] = Policy{Rate: rate, Burst: burst, ByTarget: byTarget}
+	}
+	return parsed, nil
+}
+
+// Limiter throttles calls by policies of their methods
+type Limiter struct {
+	store    Store
+	policies Policies
+	failOpen bool
+}
+
+// NewLimiter creates an object of *Limiter, failOpen lets calls through when the store fails instead of rejecting them
+func NewLimiter(store Store, policies Policies, failOpen bool) *Limiter {
+	return &Limiter{store: store, policies: policies, failOpen: failOpen}
+}
+
+// UnaryServerInterceptor rejects calls over the limit with ResourceExhausted and retry-after header
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allow(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams over the limit, streams are never limited by target
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allow(ss.Context(), info.FullMethod, nil); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// allow takes token from bucket of the call. If the store fails, calls are let through when the limiter fails open,
+// so broken limiter doesn't take the whole service down, and rejected with Unavailable otherwise
+func (l *Limiter) allow(ctx context.Context, method string, req interface{}) error {
+	policy, ok := l.policies[method]
+	if !ok {
+		policy, ok = l.policies[AnyMethod]
+	}
+	if !ok {
+		return nil
+	}
+
+	key := method + "|" + auth.Caller(ctx)
+	if policy.ByTarget {
+		key += "|" + strings.ToLower(target(req))
+	}
+	allowed, tokens, err := l.store.Take(ctx, key, policy.Rate, policy.Burst)
+	if err != nil {
+		if l.failOpen {
+			logging.FromContext(ctx).Warnf("Limiter -> allow -> letting call through -> %v", err)
+			return nil
+		}
+		logging.FromContext(ctx).Errorf("Limiter -> allow -> %v", err)
+		return status.Error(codes.Unavailable, "rate limiter is unavailable")
+	}
+	if allowed {
+		return nil
+	}
+
+	retryAfter := time.Duration((1 - tokens) / policy.Rate * float64(time.Second))
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	if err = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterKey, seconds)); err != nil {
+		logging.FromContext(ctx).Warnf("Limiter -> allow -> SetHeader -> %v", err)
+	}
+	return status.Errorf(codes.ResourceExhausted, "rate limit of %s exceeded, retry after %s seconds", method, seconds)
+}
+
+// target returns username, email or phone of the request that the call looks up
+func target(req interface{}) string {
+	if r, ok := req.(interface{ GetUsername() string }); ok && r.GetUsername() != "" {
+		return r.GetUsername()
+	}
+	if r, ok := req.(interface{ GetEmail() string }); ok && r.GetEmail() != "" {
+		return r.GetEmail()
+	}
+	if r, ok := req.(interface{ GetPhone() string }); ok && r.GetPhone() != "" {
+		return r.GetPhone()
+	}
+	return ""
+}
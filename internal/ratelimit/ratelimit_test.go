@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const lookupMethod = "/ProfileService/GetPasswordAndIDByUsername"
+
+func TestMemoryStoreRefillsBucket(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := s.Take(context.Background(), "key", 1, 2)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, tokens, err := s.Take(context.Background(), "key", 1, 2)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Zero(t, tokens)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, tokens, err = s.Take(context.Background(), "key", 1, 2)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.InDelta(t, 0.5, tokens, 0.001)
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		allowed, _, err = s.Take(context.Background(), "key", 1, 2)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, _, err = s.Take(context.Background(), "key", 1, 2)
+	require.NoError(t, err)
+	require.False(t, allowed)
+}
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies([]string{lookupMethod + "=0.5:5:target", " * = 100:200"})
+	require.NoError(t, err)
+	require.Equal(t, Policy{Rate: 0.5, Burst: 5, ByTarget: true}, policies[lookupMethod])
+	require.Equal(t, Policy{Rate: 100, Burst: 200}, policies[AnyMethod])
+
+	for _, policy := range []string{"*", "*=1", "*=0:1", "*=1:0", "*=1:1:peer", "*=a:1"} {
+		_, err = ParsePolicies([]string{policy})
+		require.Error(t, err, policy)
+	}
+}
+
+type lookupRequest struct {
+	username string
+}
+
+func (r *lookupRequest) GetUsername() string {
+	return r.username
+}
+
+func call(l *Limiter, ctx context.Context, username string) error {
+	_, err := l.UnaryServerInterceptor()(ctx, &lookupRequest{username: username}, &grpc.UnaryServerInfo{FullMethod: lookupMethod},
+		func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	return err
+}
+
+func TestLimiterKeysByCallerAndTarget(t *testing.T) {
+	l := NewLimiter(NewMemoryStore(), Policies{lookupMethod: {Rate: 0.001, Burst: 1, ByTarget: true}}, true)
+	authService := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "auth"})
+	otherService := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "billing"})
+
+	require.NoError(t, call(l, authService, "Vladimir"))
+	require.Equal(t, codes.ResourceExhausted, status.Code(call(l, authService, "vladimir")))
+	require.NoError(t, call(l, authService, "Vitaliy"))
+	require.NoError(t, call(l, otherService, "Vladimir"))
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, float64, int) (allowed bool, tokens float64, err error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func TestLimiterLetsCallsThroughIfStoreFails(t *testing.T) {
+	l := NewLimiter(failingStore{}, Policies{AnyMethod: {Rate: 1, Burst: 1}}, true)
+
+	require.NoError(t, call(l, context.Background(), "Vladimir"))
+}
+
+func TestLimiterRejectsCallsIfStoreFailsClosed(t *testing.T) {
+	l := NewLimiter(failingStore{}, Policies{AnyMethod: {Rate: 1, Burst: 1}}, false)
+
+	require.Equal(t, codes.Unavailable, status.Code(call(l, context.Background(), "Vladimir")))
+}
+
+func TestLimiterSetsRetryAfter(t *testing.T) {
+	l := NewLimiter(NewMemoryStore(), Policies{AnyMethod: {Rate: 0.1, Burst: 1}}, true)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(l.UnaryServerInterceptor()))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	var header metadata.MD
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"10"}, header.Get(RetryAfterKey))
+}
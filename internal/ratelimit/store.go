@@ -0,0 +1,75 @@
+// Package ratelimit throttles calls with token buckets keyed by caller, method and target of the call
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Store is an interface of storage of token buckets. Take refills the bucket, takes one token from it
+// if there is one and returns tokens left
+type Store interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (allowed bool, tokens float64, err error)
+}
+
+// bucket is a token bucket at the moment of its last update
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// refill is time in which empty bucket becomes full
+	refill time.Duration
+}
+
+// MemoryStore keeps token buckets in memory of one replica
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	takes   int
+}
+
+// sweepEvery is the number of takes after which buckets that are full again are dropped
+const sweepEvery = 1024
+
+// NewMemoryStore creates an object of *MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take refills the bucket by time passed since its last update and takes one token from it if there is one
+func (s *MemoryStore) Take(_ context.Context, key string, rate float64, burst int) (allowed bool, tokens float64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updatedAt: now, refill: time.Duration(math.MaxInt64)}
+		if rate > 0 {
+			b.refill = time.Duration(float64(burst) / rate * float64(time.Second))
+		}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+	if b.tokens < 1 {
+		return false, b.tokens, nil
+	}
+	b.tokens--
+	return true, b.tokens, nil
+}
+
+// sweep drops buckets that would be full by now, so keys of one-off callers don't pile up
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) > b.refill {
+			delete(s.buckets, key)
+		}
+	}
+}
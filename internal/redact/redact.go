@@ -0,0 +1,118 @@
+// Package redact contains logrus hook and helpers that mask secrets and personal data before they reach logs
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mask replaces secret values completely
+const Mask = "[REDACTED]"
+
+var (
+	// secretKeys are normalized names of fields that hold secrets. Names are matched exactly, so fields like
+	// grpc_code, credentialId, publicKey or idempotency_key that only look similar stay readable.
+	// Bare code is a phone or recovery code
+	secretKeys = map[string]bool{
+		"password": true, "newpassword": true, "oldpassword": true, "passwordhash": true,
+		"token": true, "accesstoken": true, "refreshtoken": true, "hashedrefresh": true, "tokenhash": true, "idtoken": true,
+		"secret": true, "secretkey": true, "clientsecret": true, "privatekey": true, "apikey": true,
+		"code": true, "recoverycode": true, "recoverycodes": true, "verificationcode": true, "codehash": true, "otp": true,
+		"assertion": true, "signature": true, "sessiondata": true, "authorization": true, "cookie": true,
+	}
+	piiKeys = []string{"email", "phone", "username"}
+
+	emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phoneRegex = regexp.MustCompile(`\+[1-9][0-9]{6,14}`)
+)
+
+// Hook is a logrus hook that masks secrets and personal data in fields and message of every entry
+type Hook struct{}
+
+// NewHook creates an object of *Hook
+func NewHook() *Hook {
+	return &Hook{}
+}
+
+// Levels returns all levels because secrets must not leak on any of them
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire masks fields and message of the entry before it's formatted
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	entry.Data = Fields(entry.Data)
+	entry.Message = String(entry.Message)
+	return nil
+}
+
+// Fields returns copy of fields where values of secret keys are replaced with Mask and personal data is partially hidden
+func Fields(fields logrus.Fields) logrus.Fields {
+	safe := make(logrus.Fields, len(fields))
+	for key, value := range fields {
+		switch {
+		case isSecret(key):
+			safe[key] = Mask
+		case matches(key, piiKeys):
+			safe[key] = Partial(fmt.Sprint(value))
+		case key == logrus.ErrorKey:
+			safe[key] = String(fmt.Sprint(value))
+		default:
+			safe[key] = value
+		}
+	}
+	return safe
+}
+
+// Secrets returns copy of fields where only values of secret keys are replaced with Mask,
+// it's used where personal data must stay readable like the audit log
+func Secrets(fields map[string]interface{}) map[string]interface{} {
+	safe := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if isSecret(key) {
+			safe[key] = Mask
+			continue
+		}
+		safe[key] = value
+	}
+	return safe
+}
+
+// String masks emails and phone numbers that may be included into free text like error messages
+func String(s string) string {
+	s = emailRegex.ReplaceAllStringFunc(s, Partial)
+	return phoneRegex.ReplaceAllStringFunc(s, Partial)
+}
+
+// Partial keeps only the first and the last symbols of value so it's still possible to match logs with the user
+func Partial(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 2 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-1])
+}
+
+// isSecret checks if normalized key is one of secretKeys
+func isSecret(key string) bool {
+	return secretKeys[normalize(key)]
+}
+
+// matches checks if normalized key contains one of the words
+func matches(key string, words []string) bool {
+	key = normalize(key)
+	for _, word := range words {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases the key and drops separators, so "Refresh Token", "refresh_token" and "refreshToken" are equal
+func normalize(key string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "", ".", "").Replace(key))
+}
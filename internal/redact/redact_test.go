@@ -0,0 +1,85 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger() (*logrus.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.AddHook(NewHook())
+	return logger, &buf
+}
+
+func TestHookMasksSecretFields(t *testing.T) {
+	logger, buf := newTestLogger()
+
+	logger.WithFields(logrus.Fields{
+		"Password":      []byte("superSecretPassword"),
+		"Refresh Token": []byte("superSecretRefresh"),
+		"recovery_code": "abcde-fghij",
+		"code":          "482915",
+		"hashedRefresh": []byte("superSecretHashedRefresh"),
+		"Email":         "vladimir@example.com",
+		"Phone":         "+375291234567",
+		"Country":       "Belarus",
+	}).Error("ProfileHandler -> CreateProfile -> failed")
+
+	out := buf.String()
+	for _, secret := range []string{"superSecretPassword", "superSecretRefresh", "abcde-fghij", "482915", "superSecretHashedRefresh", "vladimir@example.com", "+375291234567"} {
+		require.NotContains(t, out, secret)
+	}
+	require.Contains(t, out, Mask)
+	require.Contains(t, out, "Belarus")
+}
+
+func TestHookMasksMessageAndError(t *testing.T) {
+	logger, buf := newTestLogger()
+
+	err := errors.New(`duplicate key value violates unique constraint: Key (email)=(vladimir@example.com) already exists`)
+	logger.WithError(err).Errorf("UpdatePhone -> phone +375291234567 is taken")
+
+	out := buf.String()
+	require.NotContains(t, out, "vladimir@example.com")
+	require.NotContains(t, out, "+375291234567")
+	require.Contains(t, out, "already exists")
+}
+
+func TestFieldsDoesNotModifyOriginal(t *testing.T) {
+	fields := logrus.Fields{"password": "1234"}
+	safe := Fields(fields)
+	require.Equal(t, Mask, safe["password"])
+	require.Equal(t, "1234", fields["password"])
+}
+
+func TestSecretsKeepsPersonalData(t *testing.T) {
+	safe := Secrets(map[string]interface{}{"password": []byte("1234"), "refreshToken": []byte("abcd"), "email": "vladimir@example.com"})
+	require.Equal(t, Mask, safe["password"])
+	require.Equal(t, Mask, safe["refreshToken"])
+	require.Equal(t, "vladimir@example.com", safe["email"])
+}
+
+func TestSecretsKeepsLookalikeKeys(t *testing.T) {
+	fields := map[string]interface{}{
+		"grpc_code": "NotFound", "grpc code": "Internal", "credentialId": "Y3JlZA", "publicKey": "cHVi",
+		"session_count": 3, "idempotency_key": "8c1f", "count": 10,
+	}
+	require.Equal(t, fields, Secrets(fields))
+	safe := Secrets(map[string]interface{}{"code_hash": "abcd", "Token Hash": "efgh", "sessionData": "ijkl", "code": "482915",
+		"hashedRefresh": "mnop"})
+	for key, value := range safe {
+		require.Equal(t, Mask, value, key)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	require.Equal(t, "V******r", Partial("Vladimir"))
+	require.Equal(t, "**", Partial("ab"))
+	require.Equal(t, "В*****р", Partial("Вальдер"))
+}
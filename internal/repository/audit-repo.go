@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/audit"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// execer is a transaction that audit event is written with, so the event is saved only together with the mutation
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// recordAudit appends event of the mutation made by the actor of the call, values of secret fields are masked.
+// Verification and login tokens, WebAuthn sessions and sign counts are short-lived state rather than data
+// of the profile, so their changes aren't recorded
+func recordAudit(ctx context.Context, tx execer, action string, targetID uuid.UUID, changes map[string]interface{}) error {
+	data, err := json.Marshal(redact.Secrets(changes))
+	if err != nil {
+		return fmt.Errorf("recordAudit -> %w", err)
+	}
+	call := audit.FromContext(ctx)
+	_, err = tx.Exec(ctx, "INSERT INTO audit_events (actor, method, action, target_id, changes) VALUES($1, $2, $3, $4, $5)",
+		call.Actor, call.Method, action, targetID, data)
+	if err != nil {
+		return fmt.Errorf("recordAudit -> %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns audit events matching the filter from the newest to the oldest
+func (r *ProfileRepository) ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	var targetID, since, until interface{}
+	if filter.TargetID != uuid.Nil {
+		targetID = filter.TargetID
+	}
+	if !filter.Since.IsZero() {
+		since = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = filter.Until
+	}
+	rows, err := r.reader(ctx).Query(ctx, `SELECT id, actor, method, action, target_id, changes, created_at FROM audit_events
+		WHERE ($1::uuid IS NULL OR target_id = $1) AND ($2::text = '' OR actor = $2) AND ($3::text = '' OR action = $3)
+		AND ($4::timestamptz IS NULL OR created_at >= $4) AND ($5::timestamptz IS NULL OR created_at < $5)
+		AND ($6::bigint = 0 OR id < $6) ORDER BY id DESC LIMIT $7`,
+		targetID, filter.Actor, filter.Action, since, until, filter.BeforeID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+	}
+	defer rows.Close()
+
+	events := []*model.AuditEvent{}
+	for rows.Next() {
+		var event model.AuditEvent
+		err = rows.Scan(&event.ID, &event.Actor, &event.Method, &event.Action, &event.TargetID, &event.Changes, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+	}
+	return events, nil
+}
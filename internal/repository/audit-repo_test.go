@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/audit"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEvents(t *testing.T) {
+	ctx := audit.WithCall(context.Background(), audit.Call{Actor: "sub:admin-console", Method: "/ProfileService/DeleteProfile"})
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vsevolod"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+	err = r.AddRefreshToken(context.Background(), []byte("newToken"), testProfile.ID)
+	require.NoError(t, err)
+	err = r.DeleteProfile(ctx, testProfile.ID)
+	require.NoError(t, err)
+
+	events, err := r.ListAuditEvents(context.Background(), &model.AuditFilter{TargetID: testProfile.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, []string{"DeleteProfile", "AddRefreshToken", "CreateProfile"},
+		[]string{events[0].Action, events[1].Action, events[2].Action})
+	require.Equal(t, "sub:admin-console", events[0].Actor)
+	require.Equal(t, "/ProfileService/DeleteProfile", events[0].Method)
+	require.Equal(t, audit.SystemActor, events[2].Actor)
+
+	var changes map[string]interface{}
+	require.NoError(t, json.Unmarshal(events[2].Changes, &changes))
+	require.Equal(t, testProfile.Username, changes["username"])
+	require.Equal(t, redact.Mask, changes["password"])
+	require.Equal(t, redact.Mask, changes["refreshToken"])
+
+	events, err = r.ListAuditEvents(context.Background(), &model.AuditFilter{TargetID: testProfile.ID, BeforeID: events[0].ID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "AddRefreshToken", events[0].Action)
+
+	events, err = r.ListAuditEvents(context.Background(), &model.AuditFilter{Actor: "sub:admin-console", Action: "DeleteProfile", Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+
+	_, err = r.pool.Exec(context.Background(), "DELETE FROM audit_events WHERE target_id = $1", testProfile.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// changesChannel is the channel that profile_changes_notify trigger notifies
+const changesChannel = "profile_changes"
+
+// GetProfileChanges returns up to limit changes of profiles with sequence greater than afterSequence in the order of sequences
+func (r *ProfileRepository) GetProfileChanges(ctx context.Context, afterSequence int64, limit int32) ([]*model.ProfileChange, error) {
+	rows, err := r.pool.Query(ctx, `SELECT seq, profile_id, operation, changed_at FROM profile_changes
+		WHERE seq > $1 ORDER BY seq LIMIT $2`, afterSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*model.ProfileChange{}
+	for rows.Next() {
+		var change model.ProfileChange
+		err = rows.Scan(&change.Sequence, &change.ProfileID, &change.Operation, &change.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+	}
+	return changes, nil
+}
+
+// GetProfileChangesHorizon returns sequence of the latest deleted change, zero means that no changes were deleted
+func (r *ProfileRepository) GetProfileChangesHorizon(ctx context.Context) (sequence int64, err error) {
+	err = r.pool.QueryRow(ctx, "SELECT seq FROM profile_changes_horizon").Scan(&sequence)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> GetProfileChangesHorizon: %w", err)
+	}
+	return sequence, nil
+}
+
+// ChangeRepository contains pgxpool
+type ChangeRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewChangeRepository creates an object of *ChangeRepository
+func NewChangeRepository(pool *pgxpool.Pool) *ChangeRepository {
+	return &ChangeRepository{pool: pool}
+}
+
+// notification is the payload of profile_changes notification
+type notification struct {
+	Sequence  int64     `json:"seq"`
+	ProfileID uuid.UUID `json:"id"`
+	Operation string    `json:"op"`
+	ChangedAt time.Time `json:"at"`
+}
+
+// Listen subscribes dedicated connection to profile changes, calls ready with the latest sequence once the subscription is
+// active and then passes every notified change to notify. It blocks until context is done or the connection breaks,
+// the connection is closed afterwards so it never returns to the pool subscribed
+func (r *ChangeRepository) Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error {
+	poolConn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("ChangeRepository -> Listen -> Acquire: %w", err)
+	}
+	conn := poolConn.Hijack()
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = conn.Close(closeCtx)
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		return fmt.Errorf("ChangeRepository -> Listen: %w", err)
+	}
+	var latest int64
+	err = conn.QueryRow(ctx, "SELECT COALESCE(MAX(seq), 0) FROM profile_changes").Scan(&latest)
+	if err != nil {
+		return fmt.Errorf("ChangeRepository -> Listen: %w", err)
+	}
+	ready(latest)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("ChangeRepository -> Listen -> WaitForNotification: %w", err)
+		}
+		var payload notification
+		if err = json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+			return fmt.Errorf("ChangeRepository -> Listen: %w", err)
+		}
+		notify(&model.ProfileChange{
+			Sequence:  payload.Sequence,
+			ProfileID: payload.ProfileID,
+			Operation: payload.Operation,
+			ChangedAt: payload.ChangedAt,
+		})
+	}
+}
+
+// DeleteChanges deletes changes older than age and moves the horizon to the latest deleted one,
+// watchers can't resume from sequences before the horizon
+func (r *ChangeRepository) DeleteChanges(ctx context.Context, age time.Duration) error {
+	_, err := r.pool.Exec(ctx, `WITH deleted AS (
+			DELETE FROM profile_changes WHERE changed_at < now() - make_interval(secs => $1) RETURNING seq
+		)
+		UPDATE profile_changes_horizon SET seq = GREATEST(seq, (SELECT MAX(seq) FROM deleted))`, age.Seconds())
+	if err != nil {
+		return fmt.Errorf("ChangeRepository -> DeleteChanges: %w", err)
+	}
+	return nil
+}
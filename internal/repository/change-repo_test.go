@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileChanges(t *testing.T) {
+	c := NewChangeRepository(r.pool)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ready := make(chan int64, 1)
+	notified := make(chan *model.ProfileChange, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Listen(ctx, func(latest int64) { ready <- latest }, func(change *model.ProfileChange) { notified <- change })
+	}()
+	latest := <-ready
+
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Stanislav"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+	err = r.UpdateEmail(context.Background(), testProfile.ID, "stanislav@example.com")
+	require.NoError(t, err)
+	err = r.DeleteProfile(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+
+	var operations []string
+	for i := 0; i < 3; i++ {
+		select {
+		case change := <-notified:
+			require.Equal(t, testProfile.ID, change.ProfileID)
+			require.Equal(t, latest+int64(i)+1, change.Sequence)
+			operations = append(operations, change.Operation)
+		case <-time.After(5 * time.Second):
+			t.Fatal("profile change wasn't notified")
+		}
+	}
+	require.Equal(t, []string{"INSERT", "UPDATE", "DELETE"}, operations)
+
+	changes, err := r.GetProfileChanges(context.Background(), latest, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, latest+1, changes[0].Sequence)
+
+	horizon, err := r.GetProfileChangesHorizon(context.Background())
+	require.NoError(t, err)
+	require.Less(t, horizon, latest+1)
+
+	cancel()
+	require.Error(t, <-done)
+
+	err = c.DeleteChanges(context.Background(), 0)
+	require.NoError(t, err)
+	horizon, err = r.GetProfileChangesHorizon(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, latest+3, horizon)
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// GetPasswordAndIDByEmail returns hash of the password and id from profiles table, unverified emails aren't looked up
+func (r *ProfileRepository) GetPasswordAndIDByEmail(ctx context.Context, email string) (id uuid.UUID, password []byte, err error) {
+	err = r.pool.QueryRow(ctx, "SELECT id, password FROM profiles WHERE email = $1 AND email_verified_at IS NOT NULL", email).Scan(&id, &password)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByEmail: %w", err)
+	}
+	return id, password, nil
+}
+
+// UpdateEmail sets new unverified email of the profile and drops its pending verification tokens
+func (r *ProfileRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE email = $1 AND email_verified_at IS NOT NULL AND id <> $2", email, id).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> QueryRow -> error: profile with such email already exists")
+	}
+
+	res, err := tx.Exec(ctx, `UPDATE profiles SET email = $1,
+		email_verified_at = CASE WHEN email = $1 THEN email_verified_at END WHERE id = $2`, email, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	_, err = tx.Exec(ctx, "DELETE FROM email_verification_tokens WHERE profile_id = $1 AND email <> $2", id, email)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+	}
+	err = recordAudit(ctx, tx, "UpdateEmail", id, map[string]interface{}{"email": email})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileUpdated, id, map[string]interface{}{"fields": []string{"email"}})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateEmail -> Commit: %w", err)
+	}
+	return nil
+}
+
+// AddEmailVerificationToken replaces verification token of the profile bound to its current email
+func (r *ProfileRepository) AddEmailVerificationToken(ctx context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, "DELETE FROM email_verification_tokens WHERE profile_id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+	}
+	res, err := tx.Exec(ctx, `INSERT INTO email_verification_tokens (token_hash, profile_id, email, expires_at)
+		SELECT $1, id, email, $2 FROM profiles WHERE id = $3 AND email IS NOT NULL`, tokenHash, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> Commit: %w", err)
+	}
+	return nil
+}
+
+// ConfirmEmail consumes unexpired verification token, marks email it was issued for as verified and returns id of the profile.
+// Email that another profile has already verified can't be verified again
+func (r *ProfileRepository) ConfirmEmail(ctx context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM email_verification_tokens t JOIN profiles p
+		ON p.email = t.email AND p.id <> t.profile_id AND p.email_verified_at IS NOT NULL WHERE t.token_hash = $1`, tokenHash).Scan(&count)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+	}
+	if count > 0 {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> QueryRow -> error: profile with such email already exists")
+	}
+
+	var email string
+	err = tx.QueryRow(ctx, `WITH token AS (
+			DELETE FROM email_verification_tokens WHERE token_hash = $1 AND expires_at > now() RETURNING profile_id, email
+		)
+		UPDATE profiles SET email_verified_at = now() FROM token
+		WHERE profiles.id = token.profile_id AND profiles.email = token.email RETURNING profiles.id, profiles.email`, tokenHash).Scan(&id, &email)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail: %w", err)
+	}
+	err = recordAudit(ctx, tx, "ConfirmEmail", id, map[string]interface{}{"email": email})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileUpdated, id, map[string]interface{}{"fields": []string{"email"}})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> Commit: %w", err)
+	}
+	return id, nil
+}
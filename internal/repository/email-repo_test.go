@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerification(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vsevolod"
+	testProfile.Email = "vsevolod@example.com"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	_, _, err = r.GetPasswordAndIDByEmail(context.Background(), testProfile.Email)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	id := testProfile.ID
+
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vsevolod2"
+	err = r.CreateProfile(context.Background(), &testProfile)
+	require.Error(t, err)
+	testProfile.ID = id
+
+	err = r.AddEmailVerificationToken(context.Background(), id, []byte("expired"), time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = r.ConfirmEmail(context.Background(), []byte("expired"))
+	require.Error(t, err)
+
+	err = r.AddEmailVerificationToken(context.Background(), id, []byte("token"), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	confirmedID, err := r.ConfirmEmail(context.Background(), []byte("token"))
+	require.NoError(t, err)
+	require.Equal(t, id, confirmedID)
+	lookedUpID, _, err := r.GetPasswordAndIDByEmail(context.Background(), testProfile.Email)
+	require.NoError(t, err)
+	require.Equal(t, id, lookedUpID)
+	_, err = r.ConfirmEmail(context.Background(), []byte("token"))
+	require.Error(t, err)
+
+	profile, err := r.GetProfileByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Email, profile.Email)
+	require.NotNil(t, profile.EmailVerifiedAt)
+
+	err = r.UpdateEmail(context.Background(), id, "vsevolod@example.org")
+	require.NoError(t, err)
+	profile, err = r.GetProfileByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Nil(t, profile.EmailVerifiedAt)
+	testProfile.Email = ""
+}
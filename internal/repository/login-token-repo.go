@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// AddLoginToken adds hash of single-use login token of the profile instead of its previous tokens, so requesting
+// tokens again and again doesn't pile up live ones and only the latest of them can be redeemed
+func (r *ProfileRepository) AddLoginToken(ctx context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddLoginToken -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, "DELETE FROM login_tokens WHERE profile_id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddLoginToken -> %w", err)
+	}
+	res, err := tx.Exec(ctx, "INSERT INTO login_tokens (token_hash, profile_id, expires_at) SELECT $1, id, $2 FROM profiles WHERE id = $3",
+		tokenHash, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddLoginToken -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddLoginToken -> Commit: %w", err)
+	}
+	return nil
+}
+
+// RedeemLoginToken deletes unexpired login token and returns id of the profile it was issued for
+func (r *ProfileRepository) RedeemLoginToken(ctx context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	err = r.pool.QueryRow(ctx, "DELETE FROM login_tokens WHERE token_hash = $1 AND expires_at > now() RETURNING profile_id", tokenHash).
+		Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> RedeemLoginToken: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginToken(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vitaliy"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	err = r.AddLoginToken(context.Background(), testProfile.ID, []byte("expired"), time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = r.RedeemLoginToken(context.Background(), []byte("expired"))
+	require.Error(t, err)
+
+	err = r.AddLoginToken(context.Background(), testProfile.ID, []byte("replaced"), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	err = r.AddLoginToken(context.Background(), testProfile.ID, []byte("token"), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	_, err = r.RedeemLoginToken(context.Background(), []byte("replaced"))
+	require.Error(t, err)
+	id, err := r.RedeemLoginToken(context.Background(), []byte("token"))
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID, id)
+	_, err = r.RedeemLoginToken(context.Background(), []byte("token"))
+	require.Error(t, err)
+
+	err = r.AddLoginToken(context.Background(), uuid.New(), []byte("orphan"), time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
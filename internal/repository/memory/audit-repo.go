@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/distuurbia/profile/internal/audit"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// recordAudit appends event of the mutation made by the actor of the call, values of secret fields are masked.
+// It's called with mu locked
+func (r *ProfileRepository) recordAudit(ctx context.Context, action string, targetID uuid.UUID, changes map[string]interface{}) {
+	data, err := json.Marshal(redact.Secrets(changes))
+	if err != nil {
+		// changes are built from plain values by the methods above, so this never happens
+		logrus.Errorf("ProfileRepository -> recordAudit -> %v", err)
+	}
+	call := audit.FromContext(ctx)
+	r.auditEvents = append(r.auditEvents, &model.AuditEvent{
+		ID:        int64(len(r.auditEvents)) + 1,
+		Actor:     call.Actor,
+		Method:    call.Method,
+		Action:    action,
+		TargetID:  targetID,
+		Changes:   data,
+		CreatedAt: r.now(),
+	})
+}
+
+// ListAuditEvents returns audit events matching the filter from the newest to the oldest
+func (r *ProfileRepository) ListAuditEvents(_ context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := []*model.AuditEvent{}
+	for i := len(r.auditEvents) - 1; i >= 0 && len(events) < int(filter.Limit); i-- {
+		event := r.auditEvents[i]
+		switch {
+		case filter.TargetID != uuid.Nil && event.TargetID != filter.TargetID,
+			filter.Actor != "" && event.Actor != filter.Actor,
+			filter.Action != "" && event.Action != filter.Action,
+			!filter.Since.IsZero() && event.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !event.CreatedAt.Before(filter.Until),
+			filter.BeforeID != 0 && event.ID >= filter.BeforeID:
+			continue
+		}
+		copied := *event
+		copied.Changes = clone(event.Changes)
+		events = append(events, &copied)
+	}
+	return events, nil
+}
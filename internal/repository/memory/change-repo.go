@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+// recordChange appends change of the profile and passes it to listeners, like profile_changes_notify trigger does.
+// It's called with mu locked, so listeners get changes in the order of their sequences
+func (r *ProfileRepository) recordChange(profileID uuid.UUID, operation string) {
+	r.lastSequence++
+	change := &model.ProfileChange{Sequence: r.lastSequence, ProfileID: profileID, Operation: operation, ChangedAt: r.now()}
+	r.changes = append(r.changes, change)
+	for _, notify := range r.listeners {
+		copied := *change
+		notify(&copied)
+	}
+}
+
+// GetProfileChanges returns up to limit changes of profiles with sequence greater than afterSequence in the order of sequences
+func (r *ProfileRepository) GetProfileChanges(_ context.Context, afterSequence int64, limit int32) ([]*model.ProfileChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changes := []*model.ProfileChange{}
+	for _, change := range r.changes {
+		if len(changes) == int(limit) {
+			break
+		}
+		if change.Sequence > afterSequence {
+			copied := *change
+			changes = append(changes, &copied)
+		}
+	}
+	return changes, nil
+}
+
+// GetProfileChangesHorizon returns sequence of the latest deleted change, zero means that no changes were deleted
+func (r *ProfileRepository) GetProfileChangesHorizon(context.Context) (sequence int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.changesHorizon, nil
+}
+
+// Listen passes every change of profiles to notify after calling ready with the latest sequence,
+// it implements watch.Source and blocks until context is done
+func (r *ProfileRepository) Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error {
+	r.mu.Lock()
+	r.lastListener++
+	listener := r.lastListener
+	r.listeners[listener] = notify
+	ready(r.lastSequence)
+	r.mu.Unlock()
+
+	<-ctx.Done()
+	r.mu.Lock()
+	delete(r.listeners, listener)
+	r.mu.Unlock()
+	return ctx.Err()
+}
+
+// DeleteChanges deletes changes older than age and moves the horizon to the latest deleted one,
+// watchers can't resume from sequences before the horizon
+func (r *ProfileRepository) DeleteChanges(_ context.Context, age time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	threshold := r.now().Add(-age)
+	kept := 0
+	for kept < len(r.changes) && r.changes[kept].ChangedAt.Before(threshold) {
+		kept++
+	}
+	if kept > 0 {
+		r.changesHorizon = r.changes[kept-1].Sequence
+	}
+	r.changes = append([]*model.ProfileChange(nil), r.changes[kept:]...)
+	return nil
+}
@@ -0,0 +1,12 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/distuurbia/profile/internal/service"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(*testing.T) service.ProfileRepository { return NewProfileRepository() })
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+// emailToken is a row of email_verification_tokens table
+type emailToken struct {
+	profileID uuid.UUID
+	email     string
+	expiresAt time.Time
+}
+
+// GetPasswordAndIDByEmail returns hash of the password and id of the profile, unverified emails aren't looked up
+func (r *ProfileRepository) GetPasswordAndIDByEmail(_ context.Context, email string) (id uuid.UUID, password []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.profiles {
+		if p.Email != "" && p.Email == email && p.EmailVerifiedAt != nil {
+			return p.ID, clone(p.Password), nil
+		}
+	}
+	return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByEmail: %w", pgxv5.ErrNoRows)
+}
+
+// UpdateEmail sets new unverified email of the profile and drops its pending verification tokens
+func (r *ProfileRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.profiles {
+		if p.ID != id && p.Email != "" && p.Email == email && p.EmailVerifiedAt != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> QueryRow -> error: profile with such email already exists")
+		}
+	}
+	p, ok := r.profiles[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+
+	changed := p.Email != email
+	if changed {
+		p.Email, p.EmailVerifiedAt = email, nil
+	}
+	for hash, token := range r.emailTokens {
+		if token.profileID == id && token.email != email {
+			delete(r.emailTokens, hash)
+		}
+	}
+	r.recordAudit(ctx, "UpdateEmail", id, map[string]interface{}{"email": email})
+	if changed {
+		r.recordChange(id, "UPDATE")
+	}
+	return nil
+}
+
+// AddEmailVerificationToken replaces verification token of the profile bound to its current email
+func (r *ProfileRepository) AddEmailVerificationToken(_ context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok || p.Email == "" {
+		return pgx.ErrNoRows
+	}
+	if token, ok := r.emailTokens[string(tokenHash)]; ok && token.profileID != id {
+		return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> error: token already exists")
+	}
+
+	for hash, token := range r.emailTokens {
+		if token.profileID == id {
+			delete(r.emailTokens, hash)
+		}
+	}
+	r.emailTokens[string(tokenHash)] = &emailToken{profileID: id, email: p.Email, expiresAt: expiresAt}
+	return nil
+}
+
+// ConfirmEmail consumes unexpired verification token, marks email it was issued for as verified and returns id of the profile.
+// Email that another profile has already verified can't be verified again
+func (r *ProfileRepository) ConfirmEmail(ctx context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	token, ok := r.emailTokens[string(tokenHash)]
+	if !ok || !token.expiresAt.After(now) {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail: %w", pgxv5.ErrNoRows)
+	}
+	p, ok := r.profiles[token.profileID]
+	if !ok || p.Email != token.email {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail: %w", pgxv5.ErrNoRows)
+	}
+	for _, other := range r.profiles {
+		if other.ID != p.ID && other.Email == p.Email && other.EmailVerifiedAt != nil {
+			return uuid.Nil, fmt.Errorf("ProfileRepository -> ConfirmEmail -> QueryRow -> error: profile with such email already exists")
+		}
+	}
+
+	delete(r.emailTokens, string(tokenHash))
+	p.EmailVerifiedAt = &now
+	r.recordAudit(ctx, "ConfirmEmail", p.ID, map[string]interface{}{"email": p.Email})
+	r.recordChange(p.ID, "UPDATE")
+	return p.ID, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+// loginToken is a row of login_tokens table
+type loginToken struct {
+	profileID uuid.UUID
+	expiresAt time.Time
+}
+
+// AddLoginToken adds hash of single-use login token of the profile instead of its previous tokens, so requesting
+// tokens again and again doesn't pile up live ones and only the latest of them can be redeemed
+func (r *ProfileRepository) AddLoginToken(_ context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[id]; !ok {
+		return pgx.ErrNoRows
+	}
+
+	for hash, token := range r.loginTokens {
+		if token.profileID == id {
+			delete(r.loginTokens, hash)
+		}
+	}
+	if _, ok := r.loginTokens[string(tokenHash)]; ok {
+		return fmt.Errorf("ProfileRepository -> AddLoginToken -> error: token already exists")
+	}
+	r.loginTokens[string(tokenHash)] = &loginToken{profileID: id, expiresAt: expiresAt}
+	return nil
+}
+
+// RedeemLoginToken deletes unexpired login token and returns id of the profile it was issued for
+func (r *ProfileRepository) RedeemLoginToken(_ context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.loginTokens[string(tokenHash)]
+	if !ok || !token.expiresAt.After(r.now()) {
+		return uuid.Nil, fmt.Errorf("ProfileRepository -> RedeemLoginToken: %w", pgxv5.ErrNoRows)
+	}
+	delete(r.loginTokens, string(tokenHash))
+	return token.profileID, nil
+}
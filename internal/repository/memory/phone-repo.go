@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+// phoneCode is a row of phone_verification_codes table
+type phoneCode struct {
+	phone        string
+	codeHash     []byte
+	attemptsLeft int
+	expiresAt    time.Time
+}
+
+// GetPasswordAndIDByPhone returns hash of the password and id of the profile, only verified phones are looked up
+func (r *ProfileRepository) GetPasswordAndIDByPhone(_ context.Context, phone string) (id uuid.UUID, password []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.profiles {
+		if p.Phone != "" && p.Phone == phone && p.PhoneVerifiedAt != nil {
+			return p.ID, clone(p.Password), nil
+		}
+	}
+	return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByPhone: %w", pgxv5.ErrNoRows)
+}
+
+// UpdatePhone sets new unverified phone of the profile and drops its pending verification code
+func (r *ProfileRepository) UpdatePhone(ctx context.Context, id uuid.UUID, phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.profiles {
+		if p.ID != id && p.Phone != "" && p.Phone == phone {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> QueryRow -> error: profile with such phone already exists")
+		}
+	}
+	p, ok := r.profiles[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+
+	changed := p.Phone != phone
+	if changed {
+		p.Phone, p.PhoneVerifiedAt = phone, nil
+	}
+	if code, ok := r.phoneCodes[id]; ok && code.phone != phone {
+		delete(r.phoneCodes, id)
+	}
+	r.recordAudit(ctx, "UpdatePhone", id, map[string]interface{}{"phone": phone})
+	if changed {
+		r.recordChange(id, "UPDATE")
+	}
+	return nil
+}
+
+// AddPhoneVerificationCode replaces verification code of the profile bound to its current phone
+func (r *ProfileRepository) AddPhoneVerificationCode(_ context.Context, id uuid.UUID, codeHash []byte, attempts int, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok || p.Phone == "" {
+		return pgx.ErrNoRows
+	}
+	r.phoneCodes[id] = &phoneCode{phone: p.Phone, codeHash: clone(codeHash), attemptsLeft: attempts, expiresAt: expiresAt}
+	return nil
+}
+
+// UsePhoneVerificationAttempt takes one attempt of unexpired verification code of the profile
+// and returns hash of that code with the phone it was issued for
+func (r *ProfileRepository) UsePhoneVerificationAttempt(_ context.Context, id uuid.UUID) (codeHash []byte, phone string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code, ok := r.phoneCodes[id]
+	if !ok || code.attemptsLeft <= 0 || !code.expiresAt.After(r.now()) {
+		return nil, "", fmt.Errorf("ProfileRepository -> UsePhoneVerificationAttempt: %w", pgxv5.ErrNoRows)
+	}
+	code.attemptsLeft--
+	return clone(code.codeHash), code.phone, nil
+}
+
+// ConfirmPhone drops verification code of the profile and marks the phone as verified if it's still the phone of the profile
+func (r *ProfileRepository) ConfirmPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok || p.Phone == "" || p.Phone != phone {
+		return pgx.ErrNoRows
+	}
+
+	delete(r.phoneCodes, id)
+	now := r.now()
+	p.PhoneVerifiedAt = &now
+	r.recordAudit(ctx, "ConfirmPhone", id, map[string]interface{}{"phone": phone})
+	r.recordChange(id, "UPDATE")
+	return nil
+}
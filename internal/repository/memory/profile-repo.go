@@ -0,0 +1,219 @@
+// Package memory contains thread-safe implementation of service.ProfileRepository that keeps data in memory of the process.
+// It behaves like repository.ProfileRepository, so the server and tests can run without Postgres, but profile events
+// aren't published and webhooks aren't delivered because nothing relays them, so the service rejects webhook methods
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+// ProfileRepository keeps all tables in maps guarded by single mutex, so every method is atomic like transaction.
+// Reads that find nothing return wrapped pgx/v5 ErrNoRows and writes that change nothing return pgx ErrNoRows,
+// the same errors as repository.ProfileRepository returns
+type ProfileRepository struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	profiles         map[uuid.UUID]*model.Profile
+	recoveryCodes    map[uuid.UUID][]*recoveryCode
+	emailTokens      map[string]*emailToken
+	phoneCodes       map[uuid.UUID]*phoneCode
+	loginTokens      map[string]*loginToken
+	webAuthnSessions map[webAuthnSessionKey]*webAuthnSession
+	credentials      []*model.WebAuthnCredential
+	rolePermissions  map[string][]string
+	profileRoles     map[uuid.UUID]map[string]bool
+	auditEvents      []*model.AuditEvent
+	subscriptions    []*model.WebhookSubscription
+	changes          []*model.ProfileChange
+	lastSequence     int64
+	changesHorizon   int64
+	listeners        map[int]func(*model.ProfileChange)
+	lastListener     int
+}
+
+// NewProfileRepository creates an object of *ProfileRepository with roles that migrations create
+func NewProfileRepository() *ProfileRepository {
+	return &ProfileRepository{
+		now:              time.Now,
+		profiles:         make(map[uuid.UUID]*model.Profile),
+		recoveryCodes:    make(map[uuid.UUID][]*recoveryCode),
+		emailTokens:      make(map[string]*emailToken),
+		phoneCodes:       make(map[uuid.UUID]*phoneCode),
+		loginTokens:      make(map[string]*loginToken),
+		webAuthnSessions: make(map[webAuthnSessionKey]*webAuthnSession),
+		rolePermissions: map[string][]string{
+			"admin": {"*"},
+			"user":  {"profile.read", "profile.update"},
+		},
+		profileRoles: make(map[uuid.UUID]map[string]bool),
+		listeners:    make(map[int]func(*model.ProfileChange)),
+	}
+}
+
+// Ping reports that the repository is available, it's used by health checks instead of Postgres pool
+func (r *ProfileRepository) Ping(context.Context) error {
+	return nil
+}
+
+// CreateProfile saves copy of model.Profile, username, phone and verified email must be unique
+func (r *ProfileRepository) CreateProfile(ctx context.Context, profile *model.Profile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.profiles {
+		if p.Username == profile.Username {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such username already exists")
+		}
+		if profile.Email != "" && p.Email == profile.Email && p.EmailVerifiedAt != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such email already exists")
+		}
+		if profile.Phone != "" && p.Phone == profile.Phone {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such phone already exists")
+		}
+	}
+	if _, ok := r.profiles[profile.ID]; ok {
+		return fmt.Errorf("ProfileRepository -> CreateProfile -> error: profile with such id already exists")
+	}
+
+	r.profiles[profile.ID] = &model.Profile{
+		ID:           profile.ID,
+		Username:     profile.Username,
+		Password:     clone(profile.Password),
+		RefreshToken: clone(profile.RefreshToken),
+		Country:      profile.Country,
+		Age:          profile.Age,
+		Email:        profile.Email,
+		Phone:        profile.Phone,
+	}
+	r.recordAudit(ctx, "CreateProfile", profile.ID, map[string]interface{}{
+		"username": profile.Username, "password": profile.Password, "refreshToken": profile.RefreshToken,
+		"country": profile.Country, "age": profile.Age, "email": profile.Email, "phone": profile.Phone,
+	})
+	r.recordChange(profile.ID, "INSERT")
+	return nil
+}
+
+// GetPasswordAndIDByUsername returns hash of the password and id of the profile
+func (r *ProfileRepository) GetPasswordAndIDByUsername(_ context.Context, username string) (id uuid.UUID, password []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.profiles {
+		if p.Username == username {
+			return p.ID, clone(p.Password), nil
+		}
+	}
+	return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByUserName: %w", pgxv5.ErrNoRows)
+}
+
+// GetRefreshTokenByID returns refreshToken of the profile
+func (r *ProfileRepository) GetRefreshTokenByID(_ context.Context, id uuid.UUID) (hashedRefresh []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok {
+		return nil, fmt.Errorf("ProfileRepository -> GetRefreshTokenByName: %w", pgxv5.ErrNoRows)
+	}
+	return clone(p.RefreshToken), nil
+}
+
+// AddRefreshToken replaces refreshToken of the profile
+func (r *ProfileRepository) AddRefreshToken(ctx context.Context, refreshToken []byte, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	p.RefreshToken = clone(refreshToken)
+	r.recordAudit(ctx, "AddRefreshToken", id, map[string]interface{}{"refreshToken": refreshToken})
+	return nil
+}
+
+// DeleteProfile deletes the profile with all its codes, tokens, credentials and roles
+func (r *ProfileRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+
+	delete(r.profiles, id)
+	delete(r.recoveryCodes, id)
+	delete(r.phoneCodes, id)
+	delete(r.profileRoles, id)
+	for hash, token := range r.emailTokens {
+		if token.profileID == id {
+			delete(r.emailTokens, hash)
+		}
+	}
+	for hash, token := range r.loginTokens {
+		if token.profileID == id {
+			delete(r.loginTokens, hash)
+		}
+	}
+	for key := range r.webAuthnSessions {
+		if key.profileID == id {
+			delete(r.webAuthnSessions, key)
+		}
+	}
+	credentials := r.credentials[:0]
+	for _, credential := range r.credentials {
+		if credential.ProfileID != id {
+			credentials = append(credentials, credential)
+		}
+	}
+	r.credentials = credentials
+
+	r.recordAudit(ctx, "DeleteProfile", id, map[string]interface{}{"username": p.Username})
+	r.recordChange(id, "DELETE")
+	return nil
+}
+
+// GetProfileByID returns the profile without its password and refreshToken
+func (r *ProfileRepository) GetProfileByID(_ context.Context, id uuid.UUID) (*model.Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.profiles[id]
+	if !ok {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileByID: %w", pgxv5.ErrNoRows)
+	}
+	profile := model.Profile{
+		ID:                p.ID,
+		Username:          p.Username,
+		Country:           p.Country,
+		Age:               p.Age,
+		Email:             p.Email,
+		EmailVerifiedAt:   cloneTime(p.EmailVerifiedAt),
+		Phone:             p.Phone,
+		PhoneVerifiedAt:   cloneTime(p.PhoneVerifiedAt),
+		RecoveryCodesLeft: r.recoveryCodesLeft(id),
+	}
+	return &profile, nil
+}
+
+// clone returns copy of b, so callers can't change kept data through returned or passed slices
+func clone(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// cloneTime returns copy of t
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrNoRows(t *testing.T) {
+	r := NewProfileRepository()
+	missing := uuid.New()
+
+	_, _, err := r.GetPasswordAndIDByUsername(context.Background(), "nobody")
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.GetProfileByID(context.Background(), missing)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.RedeemLoginToken(context.Background(), []byte("token"))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	require.ErrorIs(t, r.AddRefreshToken(context.Background(), []byte("token"), missing), pgx.ErrNoRows)
+	require.ErrorIs(t, r.DeleteProfile(context.Background(), missing), pgx.ErrNoRows)
+	require.ErrorIs(t, r.AssignRole(context.Background(), missing, "admin"), pgx.ErrNoRows)
+	require.ErrorIs(t, r.DeleteWebhookSubscription(context.Background(), missing), pgx.ErrNoRows)
+}
+
+func TestEmailVerification(t *testing.T) {
+	r := NewProfileRepository()
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	profile := repotest.NewProfile()
+	profile.Email = ""
+	ctx := context.Background()
+	require.NoError(t, r.CreateProfile(ctx, profile))
+	require.ErrorIs(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("token"), now.Add(time.Hour)), pgx.ErrNoRows)
+
+	require.NoError(t, r.UpdateEmail(ctx, profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("token"), now.Add(time.Hour)))
+	id, err := r.ConfirmEmail(ctx, []byte("token"))
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+	got, err := r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.EmailVerifiedAt)
+
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("stale"), now.Add(time.Hour)))
+	require.NoError(t, r.UpdateEmail(ctx, profile.ID, "vladimir@example.org"))
+	_, err = r.ConfirmEmail(ctx, []byte("stale"))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	got, err = r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.EmailVerifiedAt)
+}
+
+func TestPhoneVerificationAttempts(t *testing.T) {
+	r := NewProfileRepository()
+	profile := repotest.NewProfile()
+	profile.Phone = "+375291234567"
+	ctx := context.Background()
+	require.NoError(t, r.CreateProfile(ctx, profile))
+	require.NoError(t, r.AddPhoneVerificationCode(ctx, profile.ID, []byte("code"), 1, time.Now().Add(time.Minute)))
+
+	codeHash, phone, err := r.UsePhoneVerificationAttempt(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("code"), codeHash)
+	require.Equal(t, profile.Phone, phone)
+	_, _, err = r.UsePhoneVerificationAttempt(ctx, profile.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	require.ErrorIs(t, r.ConfirmPhone(ctx, profile.ID, "+375297654321"), pgx.ErrNoRows)
+	require.NoError(t, r.ConfirmPhone(ctx, profile.ID, profile.Phone))
+}
+
+func TestProfileChanges(t *testing.T) {
+	r := NewProfileRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready := make(chan int64, 1)
+	notified := make(chan *model.ProfileChange, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Listen(ctx, func(latest int64) { ready <- latest }, func(change *model.ProfileChange) { notified <- change })
+	}()
+	require.Zero(t, <-ready)
+
+	profile := repotest.NewProfile()
+	require.NoError(t, r.CreateProfile(context.Background(), profile))
+	require.NoError(t, r.AddRefreshToken(context.Background(), []byte("other"), profile.ID))
+	require.NoError(t, r.UpdateEmail(context.Background(), profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.UpdateEmail(context.Background(), profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.DeleteProfile(context.Background(), profile.ID))
+
+	for _, operation := range []string{"INSERT", "UPDATE", "DELETE"} {
+		change := <-notified
+		require.Equal(t, operation, change.Operation)
+		require.Equal(t, profile.ID, change.ProfileID)
+	}
+	changes, err := r.GetProfileChanges(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, int64(2), changes[0].Sequence)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.NoError(t, r.DeleteChanges(context.Background(), -time.Second))
+	horizon, err := r.GetProfileChangesHorizon(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), horizon)
+	changes, err = r.GetProfileChanges(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
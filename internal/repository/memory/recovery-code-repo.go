@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// recoveryCode is a row of recovery_codes table
+type recoveryCode struct {
+	hash   []byte
+	usedAt *time.Time
+}
+
+// ReplaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead
+func (r *ProfileRepository) ReplaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[profileID]; !ok {
+		return pgx.ErrNoRows
+	}
+
+	if err := r.replaceRecoveryCodes(ctx, profileID, codeHashes); err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead, r.mu must be held
+func (r *ProfileRepository) replaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error {
+	codes := make([]*recoveryCode, 0, len(codeHashes))
+	for i, codeHash := range codeHashes {
+		for _, previous := range codeHashes[:i] {
+			if bytes.Equal(previous, codeHash) {
+				return fmt.Errorf("replaceRecoveryCodes -> error: recovery code is duplicated")
+			}
+		}
+		codes = append(codes, &recoveryCode{hash: clone(codeHash)})
+	}
+	r.recoveryCodes[profileID] = codes
+	r.recordAudit(ctx, "ReplaceRecoveryCodes", profileID, map[string]interface{}{"count": len(codeHashes)})
+	return nil
+}
+
+// recoveryCodesLeft returns the number of unused recovery codes of the profile, r.mu must be held
+func (r *ProfileRepository) recoveryCodesLeft(profileID uuid.UUID) (codesLeft int32) {
+	for _, code := range r.recoveryCodes[profileID] {
+		if code.usedAt == nil {
+			codesLeft++
+		}
+	}
+	return codesLeft
+}
+
+// ConsumeRecoveryCode marks unused recovery code of the profile as used and returns the number of codes left
+func (r *ProfileRepository) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, codeHash []byte) (codesLeft int32, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var consumed bool
+	for _, code := range r.recoveryCodes[profileID] {
+		if code.usedAt == nil && !consumed && bytes.Equal(code.hash, codeHash) {
+			now := r.now()
+			code.usedAt = &now
+			consumed = true
+			continue
+		}
+		if code.usedAt == nil {
+			codesLeft++
+		}
+	}
+	if !consumed {
+		return 0, pgx.ErrNoRows
+	}
+	r.recordAudit(ctx, "ConsumeRecoveryCode", profileID, map[string]interface{}{"remaining": codesLeft})
+	return codesLeft, nil
+}
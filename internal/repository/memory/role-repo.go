@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// AssignRole grants existing role to the profile, assigning the role twice isn't an error and isn't audited
+func (r *ProfileRepository) AssignRole(ctx context.Context, id uuid.UUID, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, profileExists := r.profiles[id]
+	_, roleExists := r.rolePermissions[role]
+	if !profileExists || !roleExists {
+		return pgx.ErrNoRows
+	}
+	if r.profileRoles[id][role] {
+		return nil
+	}
+
+	if r.profileRoles[id] == nil {
+		r.profileRoles[id] = make(map[string]bool)
+	}
+	r.profileRoles[id][role] = true
+	r.recordAudit(ctx, "AssignRole", id, map[string]interface{}{"role": role})
+	return nil
+}
+
+// RevokeRole takes the role away from the profile
+func (r *ProfileRepository) RevokeRole(ctx context.Context, id uuid.UUID, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.profileRoles[id][role] {
+		return pgx.ErrNoRows
+	}
+	delete(r.profileRoles[id], role)
+	r.recordAudit(ctx, "RevokeRole", id, map[string]interface{}{"role": role})
+	return nil
+}
+
+// GetRoles returns names of roles assigned to the profile
+func (r *ProfileRepository) GetRoles(_ context.Context, id uuid.UUID) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	roles := []string{}
+	for role := range r.profileRoles[id] {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles, nil
+}
+
+// GetPermissions returns distinct permissions of all roles of the profile
+func (r *ProfileRepository) GetPermissions(_ context.Context, id uuid.UUID) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := make(map[string]bool)
+	permissions := []string{}
+	for role := range r.profileRoles[id] {
+		for _, permission := range r.rolePermissions[role] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
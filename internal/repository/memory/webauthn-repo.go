@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+// webAuthnSessionKey is primary key of webauthn_sessions table
+type webAuthnSessionKey struct {
+	profileID uuid.UUID
+	ceremony  string
+}
+
+// webAuthnSession is a row of webauthn_sessions table
+type webAuthnSession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// AddWebAuthnSession saves state of the WebAuthn ceremony of the profile instead of the previous one
+func (r *ProfileRepository) AddWebAuthnSession(_ context.Context, id uuid.UUID, ceremony string, sessionData []byte, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.profiles[id]; !ok {
+		return pgx.ErrNoRows
+	}
+	r.webAuthnSessions[webAuthnSessionKey{profileID: id, ceremony: ceremony}] = &webAuthnSession{data: clone(sessionData), expiresAt: expiresAt}
+	return nil
+}
+
+// PopWebAuthnSession deletes unexpired state of the WebAuthn ceremony of the profile and returns it
+func (r *ProfileRepository) PopWebAuthnSession(_ context.Context, id uuid.UUID, ceremony string) (sessionData []byte, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := webAuthnSessionKey{profileID: id, ceremony: ceremony}
+	session, ok := r.webAuthnSessions[key]
+	if !ok || !session.expiresAt.After(r.now()) {
+		return nil, fmt.Errorf("ProfileRepository -> PopWebAuthnSession -> %w", pgxv5.ErrNoRows)
+	}
+	delete(r.webAuthnSessions, key)
+	return session.data, nil
+}
+
+// AddWebAuthnCredential saves copy of model.WebAuthnCredential and fills its CreatedAt. If it's the first credential
+// of the profile and the profile has no unused recovery codes, recoveryCodeHashes replace its recovery codes under the same lock
+func (r *ProfileRepository) AddWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredential,
+	recoveryCodeHashes [][]byte) (recoveryCodesStored bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	first := true
+	for _, c := range r.credentials {
+		if bytes.Equal(c.ID, credential.ID) {
+			return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> QueryRow -> error: credential with such id already exists")
+		}
+		if c.ProfileID == credential.ProfileID {
+			first = false
+		}
+	}
+	if _, ok := r.profiles[credential.ProfileID]; !ok {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> error: profile doesn't exist")
+	}
+	recoveryCodesStored = len(recoveryCodeHashes) > 0 && first && r.recoveryCodesLeft(credential.ProfileID) == 0
+	if recoveryCodesStored {
+		if err = r.replaceRecoveryCodes(ctx, credential.ProfileID, recoveryCodeHashes); err != nil {
+			return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+	}
+
+	credential.CreatedAt = r.now()
+	r.credentials = append(r.credentials, cloneCredential(credential))
+	r.recordAudit(ctx, "AddWebAuthnCredential", credential.ProfileID, map[string]interface{}{
+		"credentialId": credential.ID, "publicKey": credential.PublicKey, "transports": credential.Transports,
+		"aaguid": credential.AAGUID, "attestationType": credential.AttestationType,
+	})
+	return recoveryCodesStored, nil
+}
+
+// GetWebAuthnCredentials returns all WebAuthn credentials of the profile in the order they were added
+func (r *ProfileRepository) GetWebAuthnCredentials(_ context.Context, id uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var credentials []*model.WebAuthnCredential
+	for _, c := range r.credentials {
+		if c.ProfileID == id {
+			credentials = append(credentials, cloneCredential(c))
+		}
+	}
+	return credentials, nil
+}
+
+// UpdateWebAuthnSignCount sets new sign count of the credential only if it grows, so replayed or cloned
+// authenticator can't move the counter back
+func (r *ProfileRepository) UpdateWebAuthnSignCount(_ context.Context, id uuid.UUID, credentialID []byte, signCount uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.credentials {
+		if c.ProfileID == id && bytes.Equal(c.ID, credentialID) && (c.SignCount < signCount || c.SignCount == 0 && signCount == 0) {
+			now := r.now()
+			c.SignCount, c.LastUsedAt = signCount, &now
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+// DeleteWebAuthnCredential deletes exact credential of the profile
+func (r *ProfileRepository) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID, credentialID []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.credentials {
+		if c.ProfileID == id && bytes.Equal(c.ID, credentialID) {
+			r.credentials = append(r.credentials[:i], r.credentials[i+1:]...)
+			r.recordAudit(ctx, "DeleteWebAuthnCredential", id, map[string]interface{}{"credentialId": credentialID})
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+// cloneCredential returns deep copy of the credential
+func cloneCredential(c *model.WebAuthnCredential) *model.WebAuthnCredential {
+	copied := *c
+	copied.ID = clone(c.ID)
+	copied.PublicKey = clone(c.PublicKey)
+	copied.AAGUID = clone(c.AAGUID)
+	copied.Transports = append([]string(nil), c.Transports...)
+	copied.LastUsedAt = cloneTime(c.LastUsedAt)
+	return &copied
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// CreateWebhookSubscription saves copy of model.WebhookSubscription and fills its CreatedAt
+func (r *ProfileRepository) CreateWebhookSubscription(_ context.Context, subscription *model.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.subscriptions {
+		if s.ID == subscription.ID {
+			return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription -> error: subscription with such id already exists")
+		}
+	}
+	subscription.CreatedAt = r.now()
+	copied := *subscription
+	copied.EventTypes = append([]string(nil), subscription.EventTypes...)
+	r.subscriptions = append(r.subscriptions, &copied)
+	return nil
+}
+
+// GetWebhookSubscriptions returns all webhook subscriptions without their secrets in the order they were created
+func (r *ProfileRepository) GetWebhookSubscriptions(_ context.Context) ([]*model.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscriptions := make([]*model.WebhookSubscription, 0, len(r.subscriptions))
+	for _, s := range r.subscriptions {
+		subscriptions = append(subscriptions, &model.WebhookSubscription{
+			ID:         s.ID,
+			URL:        s.URL,
+			EventTypes: append([]string(nil), s.EventTypes...),
+			CreatedAt:  s.CreatedAt,
+		})
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription deletes the subscription
+func (r *ProfileRepository) DeleteWebhookSubscription(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.subscriptions {
+		if s.ID == id {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+// GetWebhookAttempts returns no attempts because events of the memory repository are never delivered
+func (r *ProfileRepository) GetWebhookAttempts(context.Context, uuid.UUID, int32) ([]*model.WebhookAttempt, error) {
+	return []*model.WebhookAttempt{}, nil
+}
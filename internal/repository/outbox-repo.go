@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// addOutboxEvent saves lifecycle event of the profile in transaction of the write that caused it,
+// so the event is published only if the write is committed. Webhook deliveries of the event are queued
+// for every subscription of its type at the same time
+func addOutboxEvent(ctx context.Context, tx execer, eventType string, profileID uuid.UUID, payload map[string]interface{}) error {
+	payload["id"] = profileID
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("addOutboxEvent -> %w", err)
+	}
+	_, err = tx.Exec(ctx, `WITH event AS (
+			INSERT INTO outbox_events (event_type, profile_id, payload) VALUES($1, $2, $3) RETURNING id, created_at
+		)
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, profile_id, payload, occurred_at)
+		SELECT s.id, event.id, $1, $2, $3, event.created_at FROM event, webhook_subscriptions s WHERE $1 = ANY(s.event_types)`,
+		eventType, profileID, data)
+	if err != nil {
+		return fmt.Errorf("addOutboxEvent -> %w", err)
+	}
+	return nil
+}
+
+// OutboxRepository contains pgxpool
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates an object of *OutboxRepository
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// Relay locks up to limit oldest events that aren't locked by other replicas, passes them to publish one by one
+// and deletes published ones. Failed publish stops the batch and the rest of events are retried by the next call,
+// an event may be published twice if the transaction isn't committed after publish
+func (r *OutboxRepository) Relay(ctx context.Context, limit int, publish func(context.Context, *model.OutboxEvent) error) (published int, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("OutboxRepository -> Relay -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	rows, err := tx.Query(ctx, `SELECT id, event_type, profile_id, payload, created_at FROM outbox_events
+		ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("OutboxRepository -> Relay: %w", err)
+	}
+	var events []*model.OutboxEvent
+	for rows.Next() {
+		var event model.OutboxEvent
+		err = rows.Scan(&event.ID, &event.Type, &event.ProfileID, &event.Payload, &event.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("OutboxRepository -> Relay: %w", err)
+		}
+		events = append(events, &event)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("OutboxRepository -> Relay: %w", err)
+	}
+
+	var publishErr error
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		if publishErr = publish(ctx, event); publishErr != nil {
+			break
+		}
+		ids = append(ids, event.ID)
+	}
+	if len(ids) > 0 {
+		_, err = tx.Exec(ctx, "DELETE FROM outbox_events WHERE id = ANY($1)", ids)
+		if err != nil {
+			return 0, fmt.Errorf("OutboxRepository -> Relay: %w", err)
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("OutboxRepository -> Relay -> Commit: %w", err)
+		}
+	}
+	if publishErr != nil {
+		return len(ids), fmt.Errorf("OutboxRepository -> Relay -> %w", publishErr)
+	}
+	return len(ids), nil
+}
+
+// DeleteEvents deletes events older than age that nobody has published, it keeps the outbox bounded
+// while no publisher is configured
+func (r *OutboxRepository) DeleteEvents(ctx context.Context, age time.Duration) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM outbox_events WHERE created_at < now() - make_interval(secs => $1)", age.Seconds())
+	if err != nil {
+		return fmt.Errorf("OutboxRepository -> DeleteEvents -> %w", err)
+	}
+	return nil
+}
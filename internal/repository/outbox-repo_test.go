@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	o := NewOutboxRepository(r.pool)
+	drain := func(context.Context, *model.OutboxEvent) error { return nil }
+	for published := 1; published > 0; {
+		var err error
+		published, err = o.Relay(context.Background(), 100, drain)
+		require.NoError(t, err)
+	}
+
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vyacheslav"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+	err = r.DeleteProfile(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+
+	errBroker := errors.New("broker is unavailable")
+	published, err := o.Relay(context.Background(), 10, func(context.Context, *model.OutboxEvent) error { return errBroker })
+	require.ErrorIs(t, err, errBroker)
+	require.Zero(t, published)
+
+	var events []*model.OutboxEvent
+	published, err = o.Relay(context.Background(), 10, func(_ context.Context, event *model.OutboxEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, published)
+	require.Equal(t, model.EventProfileCreated, events[0].Type)
+	require.Equal(t, model.EventProfileDeleted, events[1].Type)
+	require.Equal(t, testProfile.ID, events[0].ProfileID)
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	require.Equal(t, testProfile.Username, payload["username"])
+	require.NotContains(t, payload, "password")
+
+	published, err = o.Relay(context.Background(), 10, drain)
+	require.NoError(t, err)
+	require.Zero(t, published)
+}
+
+func TestOutboxDeleteEvents(t *testing.T) {
+	o := NewOutboxRepository(r.pool)
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vsevolod"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	require.NoError(t, o.DeleteEvents(context.Background(), time.Hour))
+	var count int
+	err = r.pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM outbox_events WHERE profile_id = $1", testProfile.ID).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	require.NoError(t, o.DeleteEvents(context.Background(), 0))
+	err = r.pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM outbox_events").Scan(&count)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
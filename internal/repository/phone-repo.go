@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// GetPasswordAndIDByPhone returns hash of the password and id from profiles table, only verified phones are looked up
+func (r *ProfileRepository) GetPasswordAndIDByPhone(ctx context.Context, phone string) (id uuid.UUID, password []byte, err error) {
+	err = r.pool.QueryRow(ctx, "SELECT id, password FROM profiles WHERE phone = $1 AND phone_verified_at IS NOT NULL", phone).Scan(&id, &password)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByPhone: %w", err)
+	}
+	return id, password, nil
+}
+
+// UpdatePhone sets new unverified phone of the profile and drops its pending verification code
+func (r *ProfileRepository) UpdatePhone(ctx context.Context, id uuid.UUID, phone string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE phone = $1 AND id <> $2", phone, id).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+	}
+	if count > 0 {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> QueryRow -> error: profile with such phone already exists")
+	}
+
+	res, err := tx.Exec(ctx, `UPDATE profiles SET phone = $1,
+		phone_verified_at = CASE WHEN phone = $1 THEN phone_verified_at END WHERE id = $2`, phone, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	_, err = tx.Exec(ctx, "DELETE FROM phone_verification_codes WHERE profile_id = $1 AND phone <> $2", id, phone)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+	}
+	err = recordAudit(ctx, tx, "UpdatePhone", id, map[string]interface{}{"phone": phone})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileUpdated, id, map[string]interface{}{"fields": []string{"phone"}})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdatePhone -> Commit: %w", err)
+	}
+	return nil
+}
+
+// AddPhoneVerificationCode replaces verification code of the profile bound to its current phone
+func (r *ProfileRepository) AddPhoneVerificationCode(ctx context.Context, id uuid.UUID, codeHash []byte, attempts int, expiresAt time.Time) error {
+	res, err := r.pool.Exec(ctx, `INSERT INTO phone_verification_codes (profile_id, phone, code_hash, attempts_left, expires_at)
+		SELECT id, phone, $1, $2, $3 FROM profiles WHERE id = $4 AND phone IS NOT NULL
+		ON CONFLICT (profile_id) DO UPDATE SET phone = EXCLUDED.phone, code_hash = EXCLUDED.code_hash,
+		attempts_left = EXCLUDED.attempts_left, expires_at = EXCLUDED.expires_at`, codeHash, attempts, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddPhoneVerificationCode: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// UsePhoneVerificationAttempt takes one attempt of unexpired verification code of the profile
+// and returns hash of that code with the phone it was issued for
+func (r *ProfileRepository) UsePhoneVerificationAttempt(ctx context.Context, id uuid.UUID) (codeHash []byte, phone string, err error) {
+	err = r.pool.QueryRow(ctx, `UPDATE phone_verification_codes SET attempts_left = attempts_left - 1
+		WHERE profile_id = $1 AND attempts_left > 0 AND expires_at > now() RETURNING code_hash, phone`, id).Scan(&codeHash, &phone)
+	if err != nil {
+		return nil, "", fmt.Errorf("ProfileRepository -> UsePhoneVerificationAttempt: %w", err)
+	}
+	return codeHash, phone, nil
+}
+
+// ConfirmPhone drops verification code of the profile and marks the phone as verified if it's still the phone of the profile
+func (r *ProfileRepository) ConfirmPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, "DELETE FROM phone_verification_codes WHERE profile_id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+	}
+	res, err := tx.Exec(ctx, "UPDATE profiles SET phone_verified_at = now() WHERE id = $1 AND phone = $2", id, phone)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	err = recordAudit(ctx, tx, "ConfirmPhone", id, map[string]interface{}{"phone": phone})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileUpdated, id, map[string]interface{}{"fields": []string{"phone"}})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ConfirmPhone -> Commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneVerification(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vyacheslav"
+	testProfile.Phone = "+375291234567"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+	testProfile.Phone = ""
+
+	id := testProfile.ID
+	_, _, err = r.GetPasswordAndIDByPhone(context.Background(), "+375291234567")
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	err = r.AddPhoneVerificationCode(context.Background(), id, []byte("code"), 2, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		codeHash, phone, err := r.UsePhoneVerificationAttempt(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, []byte("code"), codeHash)
+		require.Equal(t, "+375291234567", phone)
+	}
+	_, _, err = r.UsePhoneVerificationAttempt(context.Background(), id)
+	require.Error(t, err)
+
+	err = r.AddPhoneVerificationCode(context.Background(), id, []byte("code"), 2, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	_, phone, err := r.UsePhoneVerificationAttempt(context.Background(), id)
+	require.NoError(t, err)
+	err = r.ConfirmPhone(context.Background(), id, phone)
+	require.NoError(t, err)
+
+	profile, err := r.GetProfileByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, profile.PhoneVerifiedAt)
+	found, _, err := r.GetPasswordAndIDByPhone(context.Background(), "+375291234567")
+	require.NoError(t, err)
+	require.Equal(t, id, found)
+
+	err = r.UpdatePhone(context.Background(), id, "+375297654321")
+	require.NoError(t, err)
+	profile, err = r.GetProfileByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, "+375297654321", profile.Phone)
+	require.Nil(t, profile.PhoneVerifiedAt)
+}
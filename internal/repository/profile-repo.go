@@ -11,9 +11,10 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// ProfileRepository contains pgxpool
+// ProfileRepository contains pgxpool of the primary and optional read replicas
 type ProfileRepository struct {
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	replicas *Replicas
 }
 
 // NewProfileRepository creates an object of *ProfileRepository
@@ -21,23 +22,83 @@ func NewProfileRepository(pool *pgxpool.Pool) *ProfileRepository {
 	return &ProfileRepository{pool: pool}
 }
 
+// NewReplicatedProfileRepository creates an object of *ProfileRepository that writes to the primary of replicas and
+// sends reads that tolerate stale rows to replicas. Lookups of passwords, refresh tokens, roles, permissions, credentials
+// and changes always read the primary because they follow writes of the same client
+func NewReplicatedProfileRepository(replicas *Replicas) *ProfileRepository {
+	return &ProfileRepository{pool: replicas.primary, replicas: replicas}
+}
+
+// reader returns pool for reads that tolerate stale rows
+func (r *ProfileRepository) reader(ctx context.Context) querier {
+	if r.replicas == nil {
+		return r.pool
+	}
+	return r.replicas.reader(ctx)
+}
+
 // CreateProfile creates the row in db with fields of model.Profile
 func (r *ProfileRepository) CreateProfile(ctx context.Context, profile *model.Profile) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateProfile -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	var count int
-	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE username = $1", profile.Username).Scan(&count)
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE username = $1", profile.Username).Scan(&count)
 	if err != nil {
 		return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
 	}
 	if count > 0 {
 		return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such username already exists")
 	}
+	if profile.Email != "" {
+		err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE email = $1 AND email_verified_at IS NOT NULL", profile.Email).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such email already exists")
+		}
+	}
 
-	_, err = r.pool.Exec(ctx, "INSERT into profiles (id, username, password, refreshToken, country, age) VALUES($1, $2, $3, $4, $5, $6)",
-		profile.ID, profile.Username, profile.Password, profile.RefreshToken, profile.Country, profile.Age)
+	if profile.Phone != "" {
+		err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE phone = $1", profile.Phone).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such phone already exists")
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT into profiles (id, username, password, refreshToken, country, age, email, phone)
+		VALUES($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''))`,
+		profile.ID, profile.Username, profile.Password, profile.RefreshToken, profile.Country, profile.Age, profile.Email, profile.Phone)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+	}
+	err = recordAudit(ctx, tx, "CreateProfile", profile.ID, map[string]interface{}{
+		"username": profile.Username, "password": profile.Password, "refreshToken": profile.RefreshToken,
+		"country": profile.Country, "age": profile.Age, "email": profile.Email, "phone": profile.Phone,
+	})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileCreated, profile.ID, map[string]interface{}{
+		"username": profile.Username, "country": profile.Country, "age": profile.Age,
+	})
 	if err != nil {
 		return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
 	}
 
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateProfile -> Commit: %w", err)
+	}
 	return nil
 }
 
@@ -62,25 +123,65 @@ func (r *ProfileRepository) GetRefreshTokenByID(ctx context.Context, id uuid.UUI
 
 // AddRefreshToken adds refreshToken to profiles table in exact row by id
 func (r *ProfileRepository) AddRefreshToken(ctx context.Context, refreshToken []byte, id uuid.UUID) error {
-	res, err := r.pool.Exec(ctx, "UPDATE profiles SET refreshtoken = $1 WHERE id = $2", refreshToken, id)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddRefreshToken -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	res, err := tx.Exec(ctx, "UPDATE profiles SET refreshtoken = $1 WHERE id = $2", refreshToken, id)
 	if err != nil {
 		return fmt.Errorf("ProfileRepository -> AddRefreshToken: %w", err)
 	}
 	if res.RowsAffected() == 0 {
 		return pgx.ErrNoRows
 	}
+	err = recordAudit(ctx, tx, "AddRefreshToken", id, map[string]interface{}{"refreshToken": refreshToken})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddRefreshToken -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddRefreshToken -> Commit: %w", err)
+	}
 	return nil
 }
 
-// DeleteProfile deletes exact row from profiles table
+// DeleteProfile deletes exact row from profiles table, the audit event keeps username of the deleted profile
 func (r *ProfileRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error {
-	res, err := r.pool.Exec(ctx, "DELETE FROM profiles WHERE id = $1", id)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteProfile -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count int
+	var username string
+	err = tx.QueryRow(ctx, `WITH deleted AS (DELETE FROM profiles WHERE id = $1 RETURNING username)
+		SELECT COUNT(*), COALESCE(MAX(username), '') FROM deleted`, id).Scan(&count, &username)
 	if err != nil {
 		return fmt.Errorf("ProfileRepository -> DeleteProfile -> error: %w", err)
 	}
-	if res.RowsAffected() == 0 {
+	if count == 0 {
 		return pgx.ErrNoRows
 	}
+	err = recordAudit(ctx, tx, "DeleteProfile", id, map[string]interface{}{"username": username})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteProfile -> %w", err)
+	}
+	err = addOutboxEvent(ctx, tx, model.EventProfileDeleted, id, map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteProfile -> %w", err)
+	}
 
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteProfile -> Commit: %w", err)
+	}
 	return nil
 }
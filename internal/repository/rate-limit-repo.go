@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimitRepository keeps token buckets of rate limiter in postgres, so all replicas share them. Keys of buckets
+// contain addresses, usernames, emails and phones of callers, so only their HMAC is stored
+type RateLimitRepository struct {
+	pool    *pgxpool.Pool
+	hashKey []byte
+}
+
+// NewRateLimitRepository creates an object of *RateLimitRepository, hashKey keys HMAC of bucket keys
+func NewRateLimitRepository(pool *pgxpool.Pool, hashKey []byte) *RateLimitRepository {
+	return &RateLimitRepository{pool: pool, hashKey: hashKey}
+}
+
+// Take refills the bucket by time passed since its last update and takes one token from it if there is one.
+// It returns tokens left, so caller can compute when the next token appears
+func (r *RateLimitRepository) Take(ctx context.Context, key string, rate float64, burst int) (allowed bool, tokens float64, err error) {
+	const refilled = "LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * $3::float8)"
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(key))
+	err = r.pool.QueryRow(ctx, `INSERT INTO rate_limit_buckets AS b (key_hash, tokens, allowed, updated_at, full_at)
+		VALUES ($1, $2::float8 - 1, $2::float8 >= 1, now(), now() + make_interval(secs => $2::float8 / $3::float8))
+		ON CONFLICT (key_hash) DO UPDATE SET
+			tokens = `+refilled+` - CASE WHEN `+refilled+` >= 1 THEN 1 ELSE 0 END,
+			allowed = `+refilled+` >= 1,
+			updated_at = now(),
+			full_at = EXCLUDED.full_at
+		RETURNING allowed, tokens`, mac.Sum(nil), float64(burst), rate).Scan(&allowed, &tokens)
+	if err != nil {
+		return false, 0, fmt.Errorf("RateLimitRepository -> Take -> %w", err)
+	}
+	return allowed, tokens, nil
+}
+
+// DeleteIdle deletes buckets that are full again, every bucket is kept for the time its policy needs to refill it
+func (r *RateLimitRepository) DeleteIdle(ctx context.Context) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE full_at < now()")
+	if err != nil {
+		return fmt.Errorf("RateLimitRepository -> DeleteIdle -> %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitTake(t *testing.T) {
+	rl := NewRateLimitRepository(r.pool, []byte("key"))
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := rl.Take(context.Background(), "/ProfileService/Login|10.0.0.1", 0.001, 3)
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+	allowed, tokens, err := rl.Take(context.Background(), "/ProfileService/Login|10.0.0.1", 0.001, 3)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Less(t, tokens, 1.0)
+
+	allowed, _, err = rl.Take(context.Background(), "/ProfileService/Login|10.0.0.2", 0.001, 3)
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestRateLimitDeleteIdle(t *testing.T) {
+	rl := NewRateLimitRepository(r.pool, []byte("key"))
+
+	_, _, err := rl.Take(context.Background(), "/ProfileService/Login|10.0.0.3", 0.001, 1)
+	require.NoError(t, err)
+	_, _, err = rl.Take(context.Background(), "/ProfileService/Login|10.0.0.4", 1000, 1)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	err = rl.DeleteIdle(context.Background())
+	require.NoError(t, err)
+	allowed, _, err := rl.Take(context.Background(), "/ProfileService/Login|10.0.0.3", 0.001, 1)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	var count int
+	err = r.pool.QueryRow(context.Background(), "SELECT COUNT(*) FROM rate_limit_buckets WHERE full_at < now()").Scan(&count)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// GetProfileByID returns profile from profiles table without its password and refreshToken
+func (r *ProfileRepository) GetProfileByID(ctx context.Context, id uuid.UUID) (*model.Profile, error) {
+	profile := model.Profile{ID: id}
+	err := r.reader(ctx).QueryRow(ctx, `SELECT username, country, age, COALESCE(email, ''), email_verified_at, COALESCE(phone, ''), phone_verified_at,
+		(SELECT COUNT(*) FROM recovery_codes WHERE profile_id = profiles.id AND used_at IS NULL)
+		FROM profiles WHERE id = $1`, id).Scan(&profile.Username, &profile.Country, &profile.Age, &profile.Email, &profile.EmailVerifiedAt,
+		&profile.Phone, &profile.PhoneVerifiedAt, &profile.RecoveryCodesLeft)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileByID: %w", err)
+	}
+	return &profile, nil
+}
+
+// ReplaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead
+func (r *ProfileRepository) ReplaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM profiles WHERE id = $1", profileID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> %w", err)
+	}
+	if count == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = replaceRecoveryCodes(ctx, tx, profileID, codeHashes)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> Commit: %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead within tx
+func replaceRecoveryCodes(ctx context.Context, tx execer, profileID uuid.UUID, codeHashes [][]byte) error {
+	_, err := tx.Exec(ctx, "DELETE FROM recovery_codes WHERE profile_id = $1", profileID)
+	if err != nil {
+		return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+	}
+	for _, codeHash := range codeHashes {
+		_, err = tx.Exec(ctx, "INSERT INTO recovery_codes (profile_id, code_hash) VALUES($1, $2)", profileID, codeHash)
+		if err != nil {
+			return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+		}
+	}
+	err = recordAudit(ctx, tx, "ReplaceRecoveryCodes", profileID, map[string]interface{}{"count": len(codeHashes)})
+	if err != nil {
+		return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode marks unused recovery code of the profile as used and returns the number of codes left
+func (r *ProfileRepository) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, codeHash []byte) (codesLeft int32, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	res, err := tx.Exec(ctx, "UPDATE recovery_codes SET used_at = now() WHERE profile_id = $1 AND code_hash = $2 AND used_at IS NULL",
+		profileID, codeHash)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return 0, pgx.ErrNoRows
+	}
+
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM recovery_codes WHERE profile_id = $1 AND used_at IS NULL", profileID).Scan(&codesLeft)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode: %w", err)
+	}
+	err = recordAudit(ctx, tx, "ConsumeRecoveryCode", profileID, map[string]interface{}{"remaining": codesLeft})
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode -> Commit: %w", err)
+	}
+	return codesLeft, nil
+}
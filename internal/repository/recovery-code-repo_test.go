@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProfileByID(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vladislav"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	profile, err := r.GetProfileByID(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Username, profile.Username)
+	require.Equal(t, testProfile.Country, profile.Country)
+	require.Equal(t, testProfile.Age, profile.Age)
+	require.Empty(t, profile.Password)
+	require.Zero(t, profile.RecoveryCodesLeft)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vlastimil"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	err = r.ReplaceRecoveryCodes(context.Background(), testProfile.ID, [][]byte{[]byte("first"), []byte("second")})
+	require.NoError(t, err)
+
+	codesLeft, err := r.ConsumeRecoveryCode(context.Background(), testProfile.ID, []byte("first"))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), codesLeft)
+
+	_, err = r.ConsumeRecoveryCode(context.Background(), testProfile.ID, []byte("first"))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	err = r.ReplaceRecoveryCodes(context.Background(), testProfile.ID, [][]byte{[]byte("third")})
+	require.NoError(t, err)
+	_, err = r.ConsumeRecoveryCode(context.Background(), testProfile.ID, []byte("second"))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	profile, err := r.GetProfileByID(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), profile.RecoveryCodesLeft)
+
+	err = r.ReplaceRecoveryCodes(context.Background(), uuid.New(), [][]byte{[]byte("fourth")})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/distuurbia/profile/internal/primary"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// querier runs queries outside of transaction, it's implemented by *pgxpool.Pool
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgxv5.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgxv5.Row
+}
+
+// Replicas routes reads that tolerate stale rows to read replicas in round-robin order. Replicas are checked
+// by Run, the ones that can't be reached within checkTimeout or lag behind the primary more than maxLag are skipped
+// until they catch up, and reads fall back to the primary when no replica is healthy
+type Replicas struct {
+	primary      *pgxpool.Pool
+	replicas     []*replica
+	maxLag       time.Duration
+	checkTimeout time.Duration
+	next         atomic.Uint64
+}
+
+// replica is a pool of read replica with the result of its latest check
+type replica struct {
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// NewReplicas creates an object of *Replicas, replicas are considered unhealthy until they are checked
+func NewReplicas(primary *pgxpool.Pool, pools []*pgxpool.Pool, maxLag, checkTimeout time.Duration) *Replicas {
+	replicas := make([]*replica, 0, len(pools))
+	for _, pool := range pools {
+		replicas = append(replicas, &replica{pool: pool})
+	}
+	return &Replicas{primary: primary, replicas: replicas, maxLag: maxLag, checkTimeout: checkTimeout}
+}
+
+// reader returns the next healthy replica or the primary if the context is pinned to it by primary.Pin or no replica is healthy
+func (r *Replicas) reader(ctx context.Context) querier {
+	if primary.Pinned(ctx) {
+		return r.primary
+	}
+	for range r.replicas {
+		next := r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+		if next.healthy.Load() {
+			return next.pool
+		}
+	}
+	return r.primary
+}
+
+// Run checks replicas right away and then every interval until context is done
+func (r *Replicas) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check measures replication lag of every replica and marks it healthy if the lag doesn't exceed maxLag.
+// Replica that doesn't stream WAL from the primary is unhealthy whatever it has replayed, otherwise lag is zero
+// while it has replayed all WAL it received, so idle primary doesn't make replicas look stale. Replicas are checked
+// in parallel and every check takes up to checkTimeout, so hung replica neither delays the others nor stays healthy
+func (r *Replicas) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(len(r.replicas))
+	for i := range r.replicas {
+		go func(i int) {
+			defer wg.Done()
+			r.check(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// check measures replication lag of the i-th replica and logs changes of its health
+func (r *Replicas) check(ctx context.Context, i int) {
+	replica := r.replicas[i]
+	ctx, cancel := context.WithTimeout(ctx, r.checkTimeout)
+	defer cancel()
+	lag, err := replicationLag(ctx, replica.pool)
+	healthy := err == nil && lag <= r.maxLag
+	if healthy != replica.healthy.Swap(healthy) {
+		switch {
+		case err != nil:
+			logrus.Warnf("Replicas -> Check -> replica %d is unavailable: %v", i, err)
+		case !healthy:
+			logrus.Warnf("Replicas -> Check -> replica %d lags %s behind the primary", i, lag)
+		default:
+			logrus.Infof("Replicas -> Check -> replica %d is healthy", i)
+		}
+	}
+}
+
+// Close closes pools of replicas, the primary is closed by its owner
+func (r *Replicas) Close() {
+	for _, replica := range r.replicas {
+		replica.pool.Close()
+	}
+}
+
+// replicationLag returns how long ago the replica replayed the latest transaction if it hasn't replayed everything
+// it received, the server that isn't in recovery has no lag. Replica whose WAL receiver isn't streaming returns error
+// because it stops receiving WAL and would report no lag while getting arbitrarily stale
+func replicationLag(ctx context.Context, pool *pgxpool.Pool) (time.Duration, error) {
+	var (
+		streaming bool
+		seconds   float64
+	)
+	err := pool.QueryRow(ctx, `SELECT NOT pg_is_in_recovery() OR EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming'),
+		COALESCE(CASE WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)::float8`).Scan(&streaming, &seconds)
+	if err != nil {
+		return 0, fmt.Errorf("replicationLag -> %w", err)
+	}
+	if !streaming {
+		return 0, fmt.Errorf("replicationLag -> error: WAL receiver isn't streaming from the primary")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
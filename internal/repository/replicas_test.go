@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/primary"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// newReplicaPool connects to the test database once more, the server isn't in recovery, so it reports no lag
+func newReplicaPool(t *testing.T) *pgxpool.Pool {
+	pool, err := pgxpool.NewWithConfig(context.Background(), r.pool.Config())
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestReplicasRouting(t *testing.T) {
+	first, second := newReplicaPool(t), newReplicaPool(t)
+	replicas := NewReplicas(r.pool, []*pgxpool.Pool{first, second}, time.Second, time.Second)
+	require.Same(t, r.pool, replicas.reader(context.Background()))
+
+	replicas.Check(context.Background())
+	require.Same(t, first, replicas.reader(context.Background()))
+	require.Same(t, second, replicas.reader(context.Background()))
+	require.Same(t, first, replicas.reader(context.Background()))
+	require.Same(t, r.pool, replicas.reader(primary.Pin(context.Background())))
+
+	second.Close()
+	replicas.Check(context.Background())
+	for i := 0; i < 3; i++ {
+		require.Same(t, first, replicas.reader(context.Background()))
+	}
+	first.Close()
+	replicas.Check(context.Background())
+	require.Same(t, r.pool, replicas.reader(context.Background()))
+}
+
+func TestReplicatedProfileRepository(t *testing.T) {
+	replicas := NewReplicas(r.pool, []*pgxpool.Pool{newReplicaPool(t)}, time.Second, time.Second)
+	replicas.Check(context.Background())
+	replicated := NewReplicatedProfileRepository(replicas)
+
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Yaroslav"
+	require.NoError(t, replicated.CreateProfile(context.Background(), &testProfile))
+	profile, err := replicated.GetProfileByID(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Username, profile.Username)
+	_, password, err := replicated.GetPasswordAndIDByUsername(context.Background(), testProfile.Username)
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Password, password)
+	roles, err := replicated.GetRoles(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+}
@@ -0,0 +1,425 @@
+// Package repotest contains conformance suite of service.ProfileRepository, every backend runs it from its own tests
+// so Postgres, in-memory and cached repositories keep the same contract
+package repotest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/distuurbia/profile/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns repository under test. Repositories may share storage between calls, so the suite creates
+// profiles with unique usernames, emails and phones and never expects the storage to be empty
+type Factory func(t *testing.T) service.ProfileRepository
+
+// Run checks that the repository behaves like repository.ProfileRepository. Reads of missing rows return wrapped
+// pgx/v5 ErrNoRows and writes that change nothing return pgx ErrNoRows
+func Run(t *testing.T, newRepository Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, r service.ProfileRepository)
+	}{
+		{"CreateProfile", testCreateProfile},
+		{"Duplicates", testDuplicates},
+		{"MissingRows", testMissingRows},
+		{"RefreshToken", testRefreshToken},
+		{"LoginTokens", testLoginTokens},
+		{"DeleteProfile", testDeleteProfile},
+		{"AuditEvents", testAuditEvents},
+		{"ConcurrentAccess", testConcurrentAccess},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepository(t))
+		})
+	}
+}
+
+// NewProfile returns profile with unique username, email and phone that isn't saved yet
+func NewProfile() *model.Profile {
+	id := uuid.New()
+	return &model.Profile{
+		ID:           id,
+		Username:     "user-" + id.String()[:8],
+		Password:     []byte("password-" + id.String()),
+		RefreshToken: []byte("refresh-" + id.String()),
+		Country:      "Belarus",
+		Age:          27,
+		Email:        id.String() + "@example.com",
+		Phone:        fmt.Sprintf("+1%010d", binary.BigEndian.Uint32(id[:4])),
+	}
+}
+
+// createProfile saves new profile and fails the test on error
+func createProfile(t *testing.T, r service.ProfileRepository) *model.Profile {
+	t.Helper()
+	profile := NewProfile()
+	require.NoError(t, r.CreateProfile(context.Background(), profile))
+	return profile
+}
+
+func testCreateProfile(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+
+	got, err := r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, got.ID)
+	require.Equal(t, profile.Username, got.Username)
+	require.Equal(t, profile.Country, got.Country)
+	require.Equal(t, profile.Age, got.Age)
+	require.Equal(t, profile.Email, got.Email)
+	require.Equal(t, profile.Phone, got.Phone)
+	require.Nil(t, got.EmailVerifiedAt)
+	require.Nil(t, got.PhoneVerifiedAt)
+	require.Empty(t, got.Password)
+	require.Empty(t, got.RefreshToken)
+
+	id, password, err := r.GetPasswordAndIDByUsername(ctx, profile.Username)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+	require.Equal(t, profile.Password, password)
+
+	// unverified contacts can't be used to log in, anyone can attach them to a profile
+	_, _, err = r.GetPasswordAndIDByEmail(ctx, profile.Email)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows, "unverified email")
+	_, _, err = r.GetPasswordAndIDByPhone(ctx, profile.Phone)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows, "unverified phone")
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, profile.ID[:], time.Now().Add(time.Hour)))
+	_, err = r.ConfirmEmail(ctx, profile.ID[:])
+	require.NoError(t, err)
+	require.NoError(t, r.ConfirmPhone(ctx, profile.ID, profile.Phone))
+
+	for name, lookup := range map[string]func() (uuid.UUID, []byte, error){
+		"email": func() (uuid.UUID, []byte, error) { return r.GetPasswordAndIDByEmail(ctx, profile.Email) },
+		"phone": func() (uuid.UUID, []byte, error) { return r.GetPasswordAndIDByPhone(ctx, profile.Phone) },
+	} {
+		id, password, err := lookup()
+		require.NoError(t, err, name)
+		require.Equal(t, profile.ID, id, name)
+		require.Equal(t, profile.Password, password, name)
+	}
+
+	refresh, err := r.GetRefreshTokenByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, profile.RefreshToken, refresh)
+}
+
+func testDuplicates(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+
+	duplicate := NewProfile()
+	duplicate.Username = profile.Username
+	require.Error(t, r.CreateProfile(ctx, duplicate), "username")
+	duplicate = NewProfile()
+	duplicate.Phone = profile.Phone
+	require.Error(t, r.CreateProfile(ctx, duplicate), "phone")
+
+	// unverified email may be claimed by several profiles, the first one to verify it owns it
+	duplicate = NewProfile()
+	duplicate.Email = profile.Email
+	require.NoError(t, r.CreateProfile(ctx, duplicate), "unverified email")
+	other := createProfile(t, r)
+	require.NoError(t, r.UpdateEmail(ctx, other.ID, profile.Email))
+	require.Error(t, r.UpdatePhone(ctx, other.ID, profile.Phone))
+	require.NoError(t, r.AddEmailVerificationToken(ctx, other.ID, []byte(other.Username), time.Now().Add(time.Minute)))
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte(profile.Username), time.Now().Add(time.Minute)))
+	_, err := r.ConfirmEmail(ctx, []byte(profile.Username))
+	require.NoError(t, err)
+	_, err = r.ConfirmEmail(ctx, []byte(other.Username))
+	require.Error(t, err, "verified email")
+
+	duplicate = NewProfile()
+	duplicate.Email = profile.Email
+	require.Error(t, r.CreateProfile(ctx, duplicate), "verified email")
+	require.Error(t, r.UpdateEmail(ctx, createProfile(t, r).ID, profile.Email))
+
+	for i := 0; i < 2; i++ {
+		withoutContacts := NewProfile()
+		withoutContacts.Email, withoutContacts.Phone = "", ""
+		require.NoError(t, r.CreateProfile(ctx, withoutContacts))
+	}
+}
+
+func testMissingRows(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	missing := NewProfile()
+
+	_, _, err := r.GetPasswordAndIDByUsername(ctx, missing.Username)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, _, err = r.GetPasswordAndIDByEmail(ctx, missing.Email)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, _, err = r.GetPasswordAndIDByPhone(ctx, missing.Phone)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.GetRefreshTokenByID(ctx, missing.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.GetProfileByID(ctx, missing.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.RedeemLoginToken(ctx, []byte(missing.ID.String()))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.PopWebAuthnSession(ctx, missing.ID, "registration")
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	require.ErrorIs(t, r.AddRefreshToken(ctx, []byte("refresh"), missing.ID), pgx.ErrNoRows)
+	require.ErrorIs(t, r.DeleteProfile(ctx, missing.ID), pgx.ErrNoRows)
+	require.ErrorIs(t, r.ReplaceRecoveryCodes(ctx, missing.ID, [][]byte{[]byte("code")}), pgx.ErrNoRows)
+	require.ErrorIs(t, r.UpdateEmail(ctx, missing.ID, missing.Email), pgx.ErrNoRows)
+	require.ErrorIs(t, r.UpdatePhone(ctx, missing.ID, missing.Phone), pgx.ErrNoRows)
+	require.ErrorIs(t, r.AddLoginToken(ctx, missing.ID, []byte("token"), time.Now().Add(time.Minute)), pgx.ErrNoRows)
+	require.ErrorIs(t, r.AssignRole(ctx, missing.ID, "user"), pgx.ErrNoRows)
+	require.ErrorIs(t, r.RevokeRole(ctx, missing.ID, "user"), pgx.ErrNoRows)
+
+	profile := createProfile(t, r)
+	require.ErrorIs(t, r.AssignRole(ctx, profile.ID, "missing-role"), pgx.ErrNoRows)
+	_, err = r.ConsumeRecoveryCode(ctx, profile.ID, []byte("code"))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func testRefreshToken(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+
+	for _, token := range []string{"first", "second"} {
+		require.NoError(t, r.AddRefreshToken(ctx, []byte(token), profile.ID))
+		refresh, err := r.GetRefreshTokenByID(ctx, profile.ID)
+		require.NoError(t, err)
+		require.Equal(t, []byte(token), refresh)
+	}
+
+	require.NoError(t, r.AddLoginToken(ctx, profile.ID, []byte(profile.Username), time.Now().Add(time.Minute)))
+	id, err := r.RedeemLoginToken(ctx, []byte(profile.Username))
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+	_, err = r.RedeemLoginToken(ctx, []byte(profile.Username))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+}
+
+func testLoginTokens(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+	first, second := []byte(profile.Username+"-first"), []byte(profile.Username+"-second")
+
+	// issuing new token invalidates the previous unredeemed one
+	require.NoError(t, r.AddLoginToken(ctx, profile.ID, first, time.Now().Add(time.Minute)))
+	require.NoError(t, r.AddLoginToken(ctx, profile.ID, second, time.Now().Add(time.Minute)))
+	_, err := r.RedeemLoginToken(ctx, first)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	id, err := r.RedeemLoginToken(ctx, second)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+	_, err = r.RedeemLoginToken(ctx, second)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	// tokens of other profiles are kept
+	other := createProfile(t, r)
+	require.NoError(t, r.AddLoginToken(ctx, profile.ID, first, time.Now().Add(time.Minute)))
+	require.NoError(t, r.AddLoginToken(ctx, other.ID, second, time.Now().Add(time.Minute)))
+	id, err = r.RedeemLoginToken(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+}
+
+func testDeleteProfile(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+	require.NoError(t, r.ReplaceRecoveryCodes(ctx, profile.ID, [][]byte{[]byte("first"), []byte("second")}))
+	require.NoError(t, r.AddLoginToken(ctx, profile.ID, []byte(profile.Username), time.Now().Add(time.Minute)))
+	require.NoError(t, r.AssignRole(ctx, profile.ID, "user"))
+	stored, err := r.AddWebAuthnCredential(ctx, &model.WebAuthnCredential{ID: profile.ID[:], ProfileID: profile.ID, PublicKey: []byte("key"),
+		Transports: []string{"internal"}, AAGUID: make([]byte, 16), AttestationType: "none"}, [][]byte{[]byte("third")})
+	require.NoError(t, err)
+	require.False(t, stored, "unused recovery codes must not be replaced")
+
+	left, err := r.ConsumeRecoveryCode(ctx, profile.ID, []byte("first"))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), left)
+	_, err = r.ConsumeRecoveryCode(ctx, profile.ID, []byte("first"))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	got, err := r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), got.RecoveryCodesLeft)
+	// reads fill caches of decorators, so the deletion has to invalidate them
+	_, _, err = r.GetPasswordAndIDByUsername(ctx, profile.Username)
+	require.NoError(t, err)
+	permissions, err := r.GetPermissions(ctx, profile.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, permissions)
+	_, err = r.GetRefreshTokenByID(ctx, profile.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, r.DeleteProfile(ctx, profile.ID))
+	require.ErrorIs(t, r.DeleteProfile(ctx, profile.ID), pgx.ErrNoRows)
+
+	_, err = r.GetProfileByID(ctx, profile.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, _, err = r.GetPasswordAndIDByUsername(ctx, profile.Username)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.GetRefreshTokenByID(ctx, profile.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	_, err = r.RedeemLoginToken(ctx, []byte(profile.Username))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	roles, err := r.GetRoles(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+	permissions, err = r.GetPermissions(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Empty(t, permissions)
+	credentials, err := r.GetWebAuthnCredentials(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Empty(t, credentials)
+	_, err = r.ConsumeRecoveryCode(ctx, profile.ID, []byte("second"))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	events, err := r.ListAuditEvents(ctx, &model.AuditFilter{TargetID: profile.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, "DeleteProfile", events[0].Action)
+	require.Equal(t, "CreateProfile", events[len(events)-1].Action)
+	require.NotContains(t, string(events[len(events)-1].Changes), string(profile.Password))
+
+	recreated := NewProfile()
+	recreated.Username = profile.Username
+	require.NoError(t, r.CreateProfile(ctx, recreated))
+	id, _, err := r.GetPasswordAndIDByUsername(ctx, profile.Username)
+	require.NoError(t, err)
+	require.Equal(t, recreated.ID, id)
+}
+
+func testAuditEvents(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	profile := createProfile(t, r)
+	credential := &model.WebAuthnCredential{ID: profile.ID[:], ProfileID: profile.ID, PublicKey: []byte("public key"),
+		Transports: []string{"internal"}, AAGUID: make([]byte, 16), AttestationType: "none"}
+	_, err := r.AddWebAuthnCredential(ctx, credential, nil)
+	require.NoError(t, err)
+
+	events, err := r.ListAuditEvents(ctx, &model.AuditFilter{TargetID: profile.ID, Action: "AddWebAuthnCredential", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	var changes map[string]interface{}
+	require.NoError(t, json.Unmarshal(events[0].Changes, &changes))
+	require.Equal(t, base64.StdEncoding.EncodeToString(credential.ID), changes["credentialId"])
+	require.Equal(t, base64.StdEncoding.EncodeToString(credential.PublicKey), changes["publicKey"])
+
+	events, err = r.ListAuditEvents(ctx, &model.AuditFilter{TargetID: profile.ID, Action: "CreateProfile", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.NoError(t, json.Unmarshal(events[0].Changes, &changes))
+	require.Equal(t, profile.Username, changes["username"])
+	require.Equal(t, redact.Mask, changes["password"])
+}
+
+func testConcurrentAccess(t *testing.T, r service.ProfileRepository) {
+	ctx := context.Background()
+	const workers = 8
+	profiles := make([]*model.Profile, workers)
+	for i := range profiles {
+		profiles[i] = NewProfile()
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*3)
+	for _, profile := range profiles {
+		profile := profile
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.CreateProfile(ctx, profile); err != nil {
+				errs <- err
+				return
+			}
+			if err := r.AddRefreshToken(ctx, []byte("rotated"), profile.ID); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := r.GetProfileByID(ctx, profile.ID); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// only one of concurrent attempts may consume single-use recovery code
+	profile := profiles[0]
+	require.NoError(t, r.ReplaceRecoveryCodes(ctx, profile.ID, [][]byte{[]byte("code")}))
+	consumed := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := r.ConsumeRecoveryCode(ctx, profile.ID, []byte("code"))
+			consumed <- err
+		}()
+	}
+	wg.Wait()
+	close(consumed)
+	succeeded := 0
+	for err := range consumed {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+	require.Equal(t, 1, succeeded)
+
+	// only one of concurrent first credentials of the profile may store recovery codes
+	profile = profiles[1]
+	stored := make(chan bool, workers)
+	failed := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			credential := &model.WebAuthnCredential{ID: []byte(fmt.Sprintf("%s-%d", profile.ID, i)), ProfileID: profile.ID,
+				PublicKey: []byte("key"), Transports: []string{"internal"}, AAGUID: make([]byte, 16), AttestationType: "none"}
+			ok, err := r.AddWebAuthnCredential(ctx, credential, [][]byte{[]byte(fmt.Sprintf("code-%d", i))})
+			if err != nil {
+				failed <- err
+				return
+			}
+			stored <- ok
+		}()
+	}
+	wg.Wait()
+	close(stored)
+	close(failed)
+	for err := range failed {
+		require.NoError(t, err)
+	}
+	succeeded = 0
+	for ok := range stored {
+		if ok {
+			succeeded++
+		}
+	}
+	require.Equal(t, 1, succeeded)
+	got, err := r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), got.RecoveryCodesLeft)
+
+	for _, profile := range profiles {
+		refresh, err := r.GetRefreshTokenByID(ctx, profile.ID)
+		require.NoError(t, err)
+		require.Equal(t, []byte("rotated"), refresh)
+	}
+}
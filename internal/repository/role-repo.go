@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// AssignRole grants existing role to the profile, assigning the role twice isn't an error and isn't audited
+func (r *ProfileRepository) AssignRole(ctx context.Context, id uuid.UUID, role string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AssignRole -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var count, inserted int
+	err = tx.QueryRow(ctx, `WITH target AS (SELECT p.id, r.name FROM profiles p, roles r WHERE p.id = $1 AND r.name = $2),
+		inserted AS (INSERT INTO profile_roles (profile_id, role) SELECT id, name FROM target ON CONFLICT DO NOTHING RETURNING role)
+		SELECT (SELECT COUNT(*) FROM target), (SELECT COUNT(*) FROM inserted)`, id, role).Scan(&count, &inserted)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AssignRole -> %w", err)
+	}
+	if count == 0 {
+		return pgx.ErrNoRows
+	}
+	if inserted > 0 {
+		err = recordAudit(ctx, tx, "AssignRole", id, map[string]interface{}{"role": role})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AssignRole -> %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AssignRole -> Commit: %w", err)
+	}
+	return nil
+}
+
+// RevokeRole takes the role away from the profile
+func (r *ProfileRepository) RevokeRole(ctx context.Context, id uuid.UUID, role string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> RevokeRole -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	res, err := tx.Exec(ctx, "DELETE FROM profile_roles WHERE profile_id = $1 AND role = $2", id, role)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> RevokeRole -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	err = recordAudit(ctx, tx, "RevokeRole", id, map[string]interface{}{"role": role})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> RevokeRole -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> RevokeRole -> Commit: %w", err)
+	}
+	return nil
+}
+
+// GetRoles returns names of roles assigned to the profile, they are read from the primary like permissions,
+// so roles and permissions returned together always match
+func (r *ProfileRepository) GetRoles(ctx context.Context, id uuid.UUID) ([]string, error) {
+	return queryStrings(ctx, r.pool, "GetRoles", "SELECT role FROM profile_roles WHERE profile_id = $1 ORDER BY role", id)
+}
+
+// GetPermissions returns distinct permissions of all roles of the profile, they are read from the primary
+// so revoked role stops granting access right away
+func (r *ProfileRepository) GetPermissions(ctx context.Context, id uuid.UUID) ([]string, error) {
+	return queryStrings(ctx, r.pool, "GetPermissions", `SELECT DISTINCT rp.permission FROM profile_roles pr
+		JOIN role_permissions rp ON rp.role = pr.role WHERE pr.profile_id = $1 ORDER BY rp.permission`, id)
+}
+
+// queryStrings returns single text column of all rows of the query
+func queryStrings(ctx context.Context, q querier, method, sql string, args ...interface{}) ([]string, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+		}
+		values = append(values, value)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+	}
+	return values, nil
+}
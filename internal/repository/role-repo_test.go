@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Bogdan"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	permissions, err := r.GetPermissions(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Empty(t, permissions)
+
+	err = r.AssignRole(context.Background(), testProfile.ID, "user")
+	require.NoError(t, err)
+	err = r.AssignRole(context.Background(), testProfile.ID, "user")
+	require.NoError(t, err)
+	err = r.AssignRole(context.Background(), testProfile.ID, "admin")
+	require.NoError(t, err)
+
+	roles, err := r.GetRoles(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin", "user"}, roles)
+	permissions, err = r.GetPermissions(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"*", "profile.read", "profile.update"}, permissions)
+
+	err = r.RevokeRole(context.Background(), testProfile.ID, "admin")
+	require.NoError(t, err)
+	err = r.RevokeRole(context.Background(), testProfile.ID, "admin")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	err = r.AssignRole(context.Background(), testProfile.ID, "superuser")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	err = r.AssignRole(context.Background(), uuid.New(), "user")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/audit"
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/redact"
+	"github.com/google/uuid"
+)
+
+// recordAudit inserts event of the mutation made by the actor of the call into audit_events table in the same
+// transaction as the mutation, values of secret fields are masked
+func (r *ProfileRepository) recordAudit(ctx context.Context, tx *writeTx, action string, targetID uuid.UUID, changes map[string]interface{}) error {
+	data, err := json.Marshal(redact.Secrets(changes))
+	if err != nil {
+		return fmt.Errorf("recordAudit -> %w", err)
+	}
+	call := audit.FromContext(ctx)
+	_, err = tx.ExecContext(ctx, "INSERT INTO audit_events (actor, method, action, target_id, changes, created_at) VALUES(?, ?, ?, ?, ?, ?)",
+		call.Actor, call.Method, action, targetID, string(data), nanos(r.now()))
+	if err != nil {
+		return fmt.Errorf("recordAudit -> %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns audit events matching the filter from the newest to the oldest
+func (r *ProfileRepository) ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	var targetID, since, until interface{}
+	if filter.TargetID != uuid.Nil {
+		targetID = filter.TargetID
+	}
+	if !filter.Since.IsZero() {
+		since = nanos(filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		until = nanos(filter.Until)
+	}
+	rows, err := r.db.QueryContext(ctx, `SELECT id, actor, method, action, target_id, changes, created_at FROM audit_events
+		WHERE (?1 IS NULL OR target_id = ?1) AND (?2 = '' OR actor = ?2) AND (?3 = '' OR action = ?3)
+		AND (?4 IS NULL OR created_at >= ?4) AND (?5 IS NULL OR created_at < ?5)
+		AND (?6 = 0 OR id < ?6) ORDER BY id DESC LIMIT ?7`,
+		targetID, filter.Actor, filter.Action, since, until, filter.BeforeID, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+	}
+	defer rows.Close()
+
+	events := []*model.AuditEvent{}
+	for rows.Next() {
+		var (
+			event     model.AuditEvent
+			changes   string
+			createdAt int64
+		)
+		if err = rows.Scan(&event.ID, &event.Actor, &event.Method, &event.Action, &event.TargetID, &changes, &createdAt); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+		}
+		event.Changes = []byte(changes)
+		event.CreatedAt = time.Unix(0, createdAt)
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> ListAuditEvents -> %w", err)
+	}
+	return events, nil
+}
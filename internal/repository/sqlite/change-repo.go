@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+// recordChange inserts change of the profile into profile_changes table, listeners get it after the transaction
+// is committed, like profile_changes_notify trigger notifies them in Postgres
+func (r *ProfileRepository) recordChange(ctx context.Context, tx *writeTx, method string, profileID uuid.UUID, operation string) error {
+	change := &model.ProfileChange{ProfileID: profileID, Operation: operation, ChangedAt: r.now()}
+	err := tx.QueryRowContext(ctx, "INSERT INTO profile_changes (profile_id, operation, changed_at) VALUES(?, ?, ?) RETURNING seq",
+		profileID, operation, nanos(change.ChangedAt)).Scan(&change.Sequence)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> %s -> recordChange: %w", method, err)
+	}
+	tx.changes = append(tx.changes, change)
+	return nil
+}
+
+// GetProfileChanges returns up to limit changes of profiles with sequence greater than afterSequence in the order of sequences
+func (r *ProfileRepository) GetProfileChanges(ctx context.Context, afterSequence int64, limit int32) ([]*model.ProfileChange, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT seq, profile_id, operation, changed_at FROM profile_changes WHERE seq > ? ORDER BY seq LIMIT ?",
+		afterSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*model.ProfileChange{}
+	for rows.Next() {
+		var (
+			change    model.ProfileChange
+			changedAt int64
+		)
+		if err = rows.Scan(&change.Sequence, &change.ProfileID, &change.Operation, &changedAt); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+		}
+		change.ChangedAt = time.Unix(0, changedAt)
+		changes = append(changes, &change)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileChanges: %w", err)
+	}
+	return changes, nil
+}
+
+// GetProfileChangesHorizon returns sequence of the latest deleted change, zero means that no changes were deleted
+func (r *ProfileRepository) GetProfileChangesHorizon(ctx context.Context) (sequence int64, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT seq FROM profile_changes_horizon").Scan(&sequence)
+	if err != nil {
+		return 0, fmt.Errorf("ProfileRepository -> GetProfileChangesHorizon: %w", err)
+	}
+	return sequence, nil
+}
+
+// Listen passes every change of profiles to notify after calling ready with the latest sequence,
+// it implements watch.Source and blocks until context is done
+func (r *ProfileRepository) Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error {
+	r.mu.Lock()
+	var latest int64
+	// autoincrement keeps the latest sequence in sqlite_sequence even after old changes are deleted
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(seq), 0) FROM sqlite_sequence WHERE name = 'profile_changes'").Scan(&latest)
+	if err != nil {
+		r.mu.Unlock()
+		return fmt.Errorf("ProfileRepository -> Listen: %w", err)
+	}
+	r.lastListener++
+	listener := r.lastListener
+	r.listeners[listener] = notify
+	ready(latest)
+	r.mu.Unlock()
+
+	<-ctx.Done()
+	r.mu.Lock()
+	delete(r.listeners, listener)
+	r.mu.Unlock()
+	return ctx.Err()
+}
+
+// DeleteChanges deletes changes older than age and moves the horizon to the latest deleted one,
+// watchers can't resume from sequences before the horizon
+func (r *ProfileRepository) DeleteChanges(ctx context.Context, age time.Duration) error {
+	return r.write(ctx, "DeleteChanges", func(tx *writeTx) error {
+		threshold := nanos(r.now().Add(-age))
+		_, err := tx.ExecContext(ctx, `UPDATE profile_changes_horizon
+			SET seq = MAX(seq, COALESCE((SELECT MAX(seq) FROM profile_changes WHERE changed_at < ?), 0))`, threshold)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteChanges: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM profile_changes WHERE changed_at < ?", threshold)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteChanges: %w", err)
+		}
+		return nil
+	})
+}
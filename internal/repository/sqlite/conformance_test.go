@@ -0,0 +1,12 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/distuurbia/profile/internal/service"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) service.ProfileRepository { return openRepository(t) })
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// GetPasswordAndIDByEmail returns hash of the password and id from profiles table, unverified emails aren't looked up
+func (r *ProfileRepository) GetPasswordAndIDByEmail(ctx context.Context, email string) (id uuid.UUID, password []byte, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT id, password FROM profiles WHERE email = ? AND email_verified_at IS NOT NULL", email).Scan(&id, &password)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByEmail: %w", noRows(err))
+	}
+	return id, password, nil
+}
+
+// UpdateEmail sets new unverified email of the profile and drops its pending verification tokens
+func (r *ProfileRepository) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	return r.write(ctx, "UpdateEmail", func(tx *writeTx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE email = ? AND email_verified_at IS NOT NULL AND id <> ?", email, id).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> QueryRow -> error: profile with such email already exists")
+		}
+		var current sql.NullString
+		err = tx.QueryRowContext(ctx, "SELECT email FROM profiles WHERE id = ?", id).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+		}
+
+		changed := current.String != email
+		if changed {
+			_, err = tx.ExecContext(ctx, "UPDATE profiles SET email = NULLIF(?, ''), email_verified_at = NULL WHERE id = ?", email, id)
+			if err != nil {
+				return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+			}
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM email_verification_tokens WHERE profile_id = ? AND email <> ?", id, email)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+		}
+		if err = r.recordAudit(ctx, tx, "UpdateEmail", id, map[string]interface{}{"email": email}); err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateEmail -> %w", err)
+		}
+		if !changed {
+			return nil
+		}
+		return r.recordChange(ctx, tx, "UpdateEmail", id, "UPDATE")
+	})
+}
+
+// AddEmailVerificationToken replaces verification token of the profile bound to its current email
+func (r *ProfileRepository) AddEmailVerificationToken(ctx context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	return r.write(ctx, "AddEmailVerificationToken", func(tx *writeTx) error {
+		var email sql.NullString
+		err := tx.QueryRowContext(ctx, "SELECT email FROM profiles WHERE id = ?", id).Scan(&email)
+		if errors.Is(err, sql.ErrNoRows) || err == nil && !email.Valid {
+			return pgx.ErrNoRows
+		}
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+		}
+		var count int
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM email_verification_tokens WHERE token_hash = ? AND profile_id <> ?", tokenHash, id).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> error: token already exists")
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM email_verification_tokens WHERE profile_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO email_verification_tokens (token_hash, profile_id, email, expires_at) VALUES(?, ?, ?, ?)",
+			tokenHash, id, email.String, nanos(expiresAt))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddEmailVerificationToken -> %w", err)
+		}
+		return nil
+	})
+}
+
+// ConfirmEmail consumes unexpired verification token, marks email it was issued for as verified and returns id of the profile.
+// Email that another profile has already verified can't be verified again
+func (r *ProfileRepository) ConfirmEmail(ctx context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	err = r.write(ctx, "ConfirmEmail", func(tx *writeTx) error {
+		now := nanos(r.now())
+		var email string
+		err := tx.QueryRowContext(ctx, `SELECT t.profile_id, t.email FROM email_verification_tokens t JOIN profiles p ON p.id = t.profile_id
+			WHERE t.token_hash = ? AND t.expires_at > ? AND p.email = t.email`, tokenHash, now).Scan(&id, &email)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail: %w", noRows(err))
+		}
+		var count int
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE email = ? AND email_verified_at IS NOT NULL AND id <> ?", email, id).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail -> QueryRow -> error: profile with such email already exists")
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM email_verification_tokens WHERE token_hash = ?", tokenHash)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE profiles SET email_verified_at = ? WHERE id = ?", now, id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+		}
+		if err = r.recordAudit(ctx, tx, "ConfirmEmail", id, map[string]interface{}{"email": email}); err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmEmail -> %w", err)
+		}
+		return r.recordChange(ctx, tx, "ConfirmEmail", id, "UPDATE")
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
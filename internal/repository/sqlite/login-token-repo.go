@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AddLoginToken adds hash of single-use login token of the profile instead of its previous tokens, so requesting
+// tokens again and again doesn't pile up live ones and only the latest of them can be redeemed
+func (r *ProfileRepository) AddLoginToken(ctx context.Context, id uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	return r.write(ctx, "AddLoginToken", func(tx *writeTx) error {
+		if err := profileExists(ctx, tx, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM login_tokens WHERE profile_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddLoginToken -> %w", err)
+		}
+		var count int
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM login_tokens WHERE token_hash = ?", tokenHash).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddLoginToken -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> AddLoginToken -> error: token already exists")
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO login_tokens (token_hash, profile_id, expires_at) VALUES(?, ?, ?)", tokenHash, id, nanos(expiresAt))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddLoginToken -> %w", err)
+		}
+		return nil
+	})
+}
+
+// RedeemLoginToken deletes unexpired login token and returns id of the profile it was issued for
+func (r *ProfileRepository) RedeemLoginToken(ctx context.Context, tokenHash []byte) (id uuid.UUID, err error) {
+	err = r.write(ctx, "RedeemLoginToken", func(tx *writeTx) error {
+		err := tx.QueryRowContext(ctx, "DELETE FROM login_tokens WHERE token_hash = ? AND expires_at > ? RETURNING profile_id",
+			tokenHash, nanos(r.now())).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> RedeemLoginToken: %w", noRows(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrations are applied by Open, they are named like flyway migrations of Postgres: V<version>__<description>.sql
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migration is an embedded migration file
+type migration struct {
+	version int
+	name    string
+}
+
+// migrate applies embedded migrations that aren't applied yet in the order of their versions,
+// every migration runs in its own transaction together with the record of its version
+func migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("migrate -> %w", err)
+	}
+	var applied int
+	err = db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("migrate -> %w", err)
+	}
+
+	pending, err := listMigrations()
+	if err != nil {
+		return fmt.Errorf("migrate -> %w", err)
+	}
+	for _, m := range pending {
+		if m.version <= applied {
+			continue
+		}
+		if err = applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migrate -> %w", err)
+		}
+	}
+	return nil
+}
+
+// listMigrations returns embedded migrations sorted by version
+func listMigrations() ([]migration, error) {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("listMigrations -> %w", err)
+	}
+	list := make([]migration, 0, len(names))
+	for _, name := range names {
+		base := strings.TrimPrefix(name, "migrations/")
+		version, _, ok := strings.Cut(strings.TrimPrefix(base, "V"), "__")
+		n, err := strconv.Atoi(version)
+		if !strings.HasPrefix(base, "V") || !ok || err != nil || n <= 0 {
+			return nil, fmt.Errorf("listMigrations -> error: migration %q must be named V<version>__<description>.sql", base)
+		}
+		list = append(list, migration{version: n, name: name})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+	return list, nil
+}
+
+// applyMigration runs the migration and records its version
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	script, err := migrations.ReadFile(m.name)
+	if err != nil {
+		return fmt.Errorf("applyMigration -> %w", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("applyMigration -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.ExecContext(ctx, string(script)); err != nil {
+		return fmt.Errorf("applyMigration -> %s: %w", m.name, err)
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES(?, ?)", m.version, strings.TrimPrefix(m.name, "migrations/"))
+	if err != nil {
+		return fmt.Errorf("applyMigration -> %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("applyMigration -> Commit: %w", err)
+	}
+	return nil
+}
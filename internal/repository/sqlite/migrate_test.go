@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMigrations(t *testing.T) {
+	list, err := listMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	for i, m := range list {
+		require.Equal(t, i+1, m.version, m.name)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.db")
+	r, err := Open(context.Background(), path)
+	require.NoError(t, err)
+	profile := repotest.NewProfile()
+	require.NoError(t, r.CreateProfile(context.Background(), profile))
+	require.NoError(t, r.Close())
+
+	r, err = Open(context.Background(), path)
+	require.NoError(t, err)
+	defer r.Close()
+	got, err := r.GetProfileByID(context.Background(), profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, profile.Username, got.Username)
+	var applied int
+	require.NoError(t, r.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied))
+	list, err := listMigrations()
+	require.NoError(t, err)
+	require.Equal(t, len(list), applied)
+}
@@ -0,0 +1,138 @@
+-- Create tables of profiles with their codes, tokens, credentials and roles, audit events, webhook subscriptions
+-- and changes of profiles. uuids are kept as text, timestamps as unix nanoseconds and arrays as JSON
+create table profiles (
+	id TEXT not null,
+	username TEXT not null,
+	password BLOB,
+	refresh_token BLOB,
+	country TEXT,
+	age INTEGER,
+	email TEXT,
+	email_verified_at INTEGER,
+	phone TEXT,
+	phone_verified_at INTEGER,
+	primary key (id)
+);
+create unique index profiles_username_key on profiles (username);
+create unique index profiles_email_key on profiles (email);
+create unique index profiles_phone_key on profiles (phone);
+
+create table recovery_codes (
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	code_hash BLOB not null,
+	used_at INTEGER,
+	primary key (profile_id, code_hash)
+);
+
+create table email_verification_tokens (
+	token_hash BLOB not null,
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	email TEXT not null,
+	expires_at INTEGER not null,
+	primary key (token_hash)
+);
+
+create table phone_verification_codes (
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	phone TEXT not null,
+	code_hash BLOB not null,
+	attempts_left INTEGER not null,
+	expires_at INTEGER not null,
+	primary key (profile_id)
+);
+
+create table login_tokens (
+	token_hash BLOB not null,
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	expires_at INTEGER not null,
+	primary key (token_hash)
+);
+
+create table webauthn_credentials (
+	id BLOB not null,
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	public_key BLOB,
+	sign_count INTEGER not null,
+	transports TEXT not null,
+	aaguid BLOB,
+	attestation_type TEXT not null,
+	created_at INTEGER not null,
+	last_used_at INTEGER,
+	primary key (id)
+);
+create index webauthn_credentials_profile_id_idx on webauthn_credentials (profile_id);
+
+create table webauthn_sessions (
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	ceremony TEXT not null,
+	session_data BLOB,
+	expires_at INTEGER not null,
+	primary key (profile_id, ceremony)
+);
+
+create table roles (
+	name TEXT not null,
+	description TEXT not null,
+	primary key (name)
+);
+
+create table role_permissions (
+	role TEXT not null references roles (name) on delete cascade,
+	permission TEXT not null,
+	primary key (role, permission)
+);
+
+create table profile_roles (
+	profile_id TEXT not null references profiles (id) on delete cascade,
+	role TEXT not null references roles (name) on delete cascade,
+	granted_at INTEGER not null,
+	primary key (profile_id, role)
+);
+
+insert into roles (name, description) values
+	('admin', 'Full access to every profile'),
+	('user', 'Access to own profile');
+
+insert into role_permissions (role, permission) values
+	('admin', '*'),
+	('user', 'profile.read'),
+	('user', 'profile.update');
+
+create table audit_events (
+	id INTEGER primary key autoincrement,
+	actor TEXT not null,
+	method TEXT not null,
+	action TEXT not null,
+	target_id TEXT not null,
+	changes TEXT not null,
+	created_at INTEGER not null
+);
+create index audit_events_target_id_idx on audit_events (target_id, id);
+create index audit_events_created_at_idx on audit_events (created_at);
+
+create trigger audit_events_no_update before update on audit_events
+begin
+	select raise(abort, 'audit_events is append-only');
+end;
+
+create trigger audit_events_no_delete before delete on audit_events
+begin
+	select raise(abort, 'audit_events is append-only');
+end;
+
+create table webhook_subscriptions (
+	id TEXT not null,
+	url TEXT not null,
+	event_types TEXT not null,
+	secret TEXT not null,
+	created_at INTEGER not null,
+	primary key (id)
+);
+
+create table profile_changes (
+	seq INTEGER primary key autoincrement,
+	profile_id TEXT not null,
+	operation TEXT not null,
+	changed_at INTEGER not null
+);
+create index profile_changes_changed_at_idx on profile_changes (changed_at);
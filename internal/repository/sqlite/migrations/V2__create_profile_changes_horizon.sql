@@ -0,0 +1,13 @@
+-- Keep sequence of the latest deleted change, the oldest kept change doesn't tell whether changes before it were deleted.
+-- Changes deleted before this migration are assumed to end right before the oldest kept one
+create table profile_changes_horizon (
+	id INTEGER primary key check (id = 1),
+	seq INTEGER not null
+);
+
+insert into profile_changes_horizon (id, seq)
+	values (1, COALESCE(
+		(SELECT MIN(seq) - 1 FROM profile_changes),
+		(SELECT seq FROM sqlite_sequence WHERE name = 'profile_changes'),
+		0
+	));
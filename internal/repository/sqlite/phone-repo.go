@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// GetPasswordAndIDByPhone returns hash of the password and id from profiles table, only verified phones are looked up
+func (r *ProfileRepository) GetPasswordAndIDByPhone(ctx context.Context, phone string) (id uuid.UUID, password []byte, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT id, password FROM profiles WHERE phone = ? AND phone_verified_at IS NOT NULL", phone).Scan(&id, &password)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByPhone: %w", noRows(err))
+	}
+	return id, password, nil
+}
+
+// UpdatePhone sets new unverified phone of the profile and drops its pending verification code
+func (r *ProfileRepository) UpdatePhone(ctx context.Context, id uuid.UUID, phone string) error {
+	return r.write(ctx, "UpdatePhone", func(tx *writeTx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE phone = ? AND id <> ?", phone, id).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> QueryRow -> error: profile with such phone already exists")
+		}
+		var current sql.NullString
+		err = tx.QueryRowContext(ctx, "SELECT phone FROM profiles WHERE id = ?", id).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+		}
+
+		changed := current.String != phone
+		if changed {
+			_, err = tx.ExecContext(ctx, "UPDATE profiles SET phone = NULLIF(?, ''), phone_verified_at = NULL WHERE id = ?", phone, id)
+			if err != nil {
+				return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+			}
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM phone_verification_codes WHERE profile_id = ? AND phone <> ?", id, phone)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+		}
+		if err = r.recordAudit(ctx, tx, "UpdatePhone", id, map[string]interface{}{"phone": phone}); err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdatePhone -> %w", err)
+		}
+		if !changed {
+			return nil
+		}
+		return r.recordChange(ctx, tx, "UpdatePhone", id, "UPDATE")
+	})
+}
+
+// AddPhoneVerificationCode replaces verification code of the profile bound to its current phone
+func (r *ProfileRepository) AddPhoneVerificationCode(ctx context.Context, id uuid.UUID, codeHash []byte, attempts int, expiresAt time.Time) error {
+	return r.write(ctx, "AddPhoneVerificationCode", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, `INSERT INTO phone_verification_codes (profile_id, phone, code_hash, attempts_left, expires_at)
+			SELECT id, phone, ?, ?, ? FROM profiles WHERE id = ? AND phone IS NOT NULL
+			ON CONFLICT (profile_id) DO UPDATE SET phone = excluded.phone, code_hash = excluded.code_hash,
+			attempts_left = excluded.attempts_left, expires_at = excluded.expires_at`,
+			codeHash, attempts, nanos(expiresAt), id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddPhoneVerificationCode: %w", err)
+		}
+		return affected(res)
+	})
+}
+
+// UsePhoneVerificationAttempt takes one attempt of unexpired verification code of the profile
+// and returns hash of that code with the phone it was issued for
+func (r *ProfileRepository) UsePhoneVerificationAttempt(ctx context.Context, id uuid.UUID) (codeHash []byte, phone string, err error) {
+	err = r.write(ctx, "UsePhoneVerificationAttempt", func(tx *writeTx) error {
+		err := tx.QueryRowContext(ctx, `UPDATE phone_verification_codes SET attempts_left = attempts_left - 1
+			WHERE profile_id = ? AND attempts_left > 0 AND expires_at > ? RETURNING code_hash, phone`, id, nanos(r.now())).Scan(&codeHash, &phone)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UsePhoneVerificationAttempt: %w", noRows(err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return codeHash, phone, nil
+}
+
+// ConfirmPhone drops verification code of the profile and marks the phone as verified if it's still the phone of the profile
+func (r *ProfileRepository) ConfirmPhone(ctx context.Context, id uuid.UUID, phone string) error {
+	return r.write(ctx, "ConfirmPhone", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE profiles SET phone_verified_at = ? WHERE id = ? AND phone = ?", nanos(r.now()), id, phone)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmPhone: %w", err)
+		}
+		if err = affected(res); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM phone_verification_codes WHERE profile_id = ?", id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+		}
+		if err = r.recordAudit(ctx, tx, "ConfirmPhone", id, map[string]interface{}{"phone": phone}); err != nil {
+			return fmt.Errorf("ProfileRepository -> ConfirmPhone -> %w", err)
+		}
+		return r.recordChange(ctx, tx, "ConfirmPhone", id, "UPDATE")
+	})
+}
@@ -0,0 +1,244 @@
+// Package sqlite contains implementation of service.ProfileRepository that keeps data in SQLite file, it's meant for
+// single-node deployments that can't run Postgres. It behaves like repository.ProfileRepository, but profile events
+// aren't published and webhooks aren't delivered because nothing relays them, so the service rejects webhook methods
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+
+	// registers pure-Go "sqlite" driver
+	_ "modernc.org/sqlite"
+)
+
+// ProfileRepository contains SQLite database. Writes are serialized by mutex, so SQLite never rejects them as busy
+// and changes of profiles reach listeners in the order of their sequences. Reads that find nothing return wrapped
+// pgx/v5 ErrNoRows and writes that change nothing return pgx ErrNoRows, the same errors as repository.ProfileRepository returns
+type ProfileRepository struct {
+	db  *sql.DB
+	now func() time.Time
+
+	mu           sync.Mutex
+	listeners    map[int]func(*model.ProfileChange)
+	lastListener int
+}
+
+// Open opens SQLite database at path, creating the file if it doesn't exist, and applies embedded migrations
+func Open(ctx context.Context, path string) (*ProfileRepository, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("Open -> %w", err)
+	}
+	if err = migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("Open -> %w", err)
+	}
+	return &ProfileRepository{db: db, now: time.Now, listeners: make(map[int]func(*model.ProfileChange))}, nil
+}
+
+// Close closes the database
+func (r *ProfileRepository) Close() error {
+	return r.db.Close()
+}
+
+// Ping checks that the database is available, it's used by health checks instead of Postgres pool
+func (r *ProfileRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// writeTx is a write transaction that collects changes of profiles to notify listeners after commit
+type writeTx struct {
+	*sql.Tx
+	changes []*model.ProfileChange
+}
+
+// write runs fn in transaction and passes changes recorded by it to listeners once the transaction is committed,
+// errors of fn are returned as is
+func (r *ProfileRepository) write(ctx context.Context, method string, fn func(tx *writeTx) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sqlTx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> %s -> Begin: %w", method, err)
+	}
+	defer func() {
+		_ = sqlTx.Rollback()
+	}()
+
+	tx := &writeTx{Tx: sqlTx}
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = sqlTx.Commit(); err != nil {
+		return fmt.Errorf("ProfileRepository -> %s -> Commit: %w", method, err)
+	}
+	for _, change := range tx.changes {
+		for _, notify := range r.listeners {
+			copied := *change
+			notify(&copied)
+		}
+	}
+	return nil
+}
+
+// CreateProfile creates the row in profiles table with fields of model.Profile
+func (r *ProfileRepository) CreateProfile(ctx context.Context, profile *model.Profile) error {
+	return r.write(ctx, "CreateProfile", func(tx *writeTx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE username = ?", profile.Username).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such username already exists")
+		}
+		if profile.Email != "" {
+			err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE email = ? AND email_verified_at IS NOT NULL", profile.Email).Scan(&count)
+			if err != nil {
+				return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+			}
+			if count > 0 {
+				return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such email already exists")
+			}
+		}
+		if profile.Phone != "" {
+			err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE phone = ?", profile.Phone).Scan(&count)
+			if err != nil {
+				return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+			}
+			if count > 0 {
+				return fmt.Errorf("ProfileRepository -> CreateProfile -> QueryRow -> error: profile with such phone already exists")
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO profiles (id, username, password, refresh_token, country, age, email, phone)
+			VALUES(?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''))`,
+			profile.ID, profile.Username, profile.Password, profile.RefreshToken, profile.Country, profile.Age, profile.Email, profile.Phone)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+		}
+		err = r.recordAudit(ctx, tx, "CreateProfile", profile.ID, map[string]interface{}{
+			"username": profile.Username, "password": profile.Password, "refreshToken": profile.RefreshToken,
+			"country": profile.Country, "age": profile.Age, "email": profile.Email, "phone": profile.Phone,
+		})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateProfile -> %w", err)
+		}
+		return r.recordChange(ctx, tx, "CreateProfile", profile.ID, "INSERT")
+	})
+}
+
+// GetPasswordAndIDByUsername returns hash of the password and id from profiles table
+func (r *ProfileRepository) GetPasswordAndIDByUsername(ctx context.Context, username string) (id uuid.UUID, password []byte, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT id, password FROM profiles WHERE username = ?", username).Scan(&id, &password)
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileRepository -> GetPasswordAndIDByUserName: %w", noRows(err))
+	}
+	return id, password, nil
+}
+
+// GetRefreshTokenByID returnes refresh token from profiles table from exact row by id
+func (r *ProfileRepository) GetRefreshTokenByID(ctx context.Context, id uuid.UUID) (hashedRefresh []byte, err error) {
+	err = r.db.QueryRowContext(ctx, "SELECT refresh_token FROM profiles WHERE id = ?", id).Scan(&hashedRefresh)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetRefreshTokenByName: %w", noRows(err))
+	}
+	return hashedRefresh, nil
+}
+
+// AddRefreshToken adds refresh token to profiles table in exact row by id
+func (r *ProfileRepository) AddRefreshToken(ctx context.Context, refreshToken []byte, id uuid.UUID) error {
+	return r.write(ctx, "AddRefreshToken", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE profiles SET refresh_token = ? WHERE id = ?", refreshToken, id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddRefreshToken: %w", err)
+		}
+		if err = affected(res); err != nil {
+			return err
+		}
+		err = r.recordAudit(ctx, tx, "AddRefreshToken", id, map[string]interface{}{"refreshToken": refreshToken})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddRefreshToken -> %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteProfile deletes exact row from profiles table with all its codes, tokens, credentials and roles,
+// the audit event keeps username of the deleted profile
+func (r *ProfileRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error {
+	return r.write(ctx, "DeleteProfile", func(tx *writeTx) error {
+		var username string
+		err := tx.QueryRowContext(ctx, "DELETE FROM profiles WHERE id = ? RETURNING username", id).Scan(&username)
+		if errors.Is(err, sql.ErrNoRows) {
+			return pgx.ErrNoRows
+		}
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteProfile -> error: %w", err)
+		}
+		err = r.recordAudit(ctx, tx, "DeleteProfile", id, map[string]interface{}{"username": username})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteProfile -> %w", err)
+		}
+		return r.recordChange(ctx, tx, "DeleteProfile", id, "DELETE")
+	})
+}
+
+// GetProfileByID returns profile from profiles table without its password and refresh token
+func (r *ProfileRepository) GetProfileByID(ctx context.Context, id uuid.UUID) (*model.Profile, error) {
+	profile := model.Profile{ID: id}
+	var emailVerifiedAt, phoneVerifiedAt sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `SELECT username, country, age, COALESCE(email, ''), email_verified_at, COALESCE(phone, ''), phone_verified_at,
+		(SELECT COUNT(*) FROM recovery_codes WHERE profile_id = profiles.id AND used_at IS NULL)
+		FROM profiles WHERE id = ?`, id).Scan(&profile.Username, &profile.Country, &profile.Age, &profile.Email, &emailVerifiedAt,
+		&profile.Phone, &phoneVerifiedAt, &profile.RecoveryCodesLeft)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetProfileByID: %w", noRows(err))
+	}
+	profile.EmailVerifiedAt = toTime(emailVerifiedAt)
+	profile.PhoneVerifiedAt = toTime(phoneVerifiedAt)
+	return &profile, nil
+}
+
+// noRows replaces sql.ErrNoRows with pgx/v5 ErrNoRows that callers of repositories check
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgxv5.ErrNoRows
+	}
+	return err
+}
+
+// affected returns pgx ErrNoRows if the statement changed no rows
+func affected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("affected -> %w", err)
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// nanos returns t as unix nanoseconds that timestamps are kept in
+func nanos(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+// toTime converts nullable unix nanoseconds to time
+func toTime(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := time.Unix(0, n.Int64)
+	return &t
+}
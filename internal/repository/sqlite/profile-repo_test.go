@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/repository/repotest"
+	"github.com/jackc/pgx"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// openRepository opens repository in a new file that is removed after the test
+func openRepository(t *testing.T) *ProfileRepository {
+	t.Helper()
+	r, err := Open(context.Background(), filepath.Join(t.TempDir(), "profile.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, r.Close())
+	})
+	return r
+}
+
+func TestEmailVerification(t *testing.T) {
+	r := openRepository(t)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	profile := repotest.NewProfile()
+	profile.Email = ""
+	ctx := context.Background()
+	require.NoError(t, r.CreateProfile(ctx, profile))
+	require.ErrorIs(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("token"), now.Add(time.Hour)), pgx.ErrNoRows)
+
+	require.NoError(t, r.UpdateEmail(ctx, profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("token"), now.Add(time.Hour)))
+	id, err := r.ConfirmEmail(ctx, []byte("token"))
+	require.NoError(t, err)
+	require.Equal(t, profile.ID, id)
+	got, err := r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.EmailVerifiedAt)
+	require.True(t, now.Equal(*got.EmailVerifiedAt))
+
+	require.NoError(t, r.AddEmailVerificationToken(ctx, profile.ID, []byte("stale"), now.Add(time.Hour)))
+	require.NoError(t, r.UpdateEmail(ctx, profile.ID, "vladimir@example.org"))
+	_, err = r.ConfirmEmail(ctx, []byte("stale"))
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+	got, err = r.GetProfileByID(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.EmailVerifiedAt)
+}
+
+func TestPhoneVerificationAttempts(t *testing.T) {
+	r := openRepository(t)
+	profile := repotest.NewProfile()
+	profile.Phone = "+375291234567"
+	ctx := context.Background()
+	require.NoError(t, r.CreateProfile(ctx, profile))
+	require.NoError(t, r.AddPhoneVerificationCode(ctx, profile.ID, []byte("old"), 3, time.Now().Add(time.Minute)))
+	require.NoError(t, r.AddPhoneVerificationCode(ctx, profile.ID, []byte("code"), 1, time.Now().Add(time.Minute)))
+
+	codeHash, phone, err := r.UsePhoneVerificationAttempt(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("code"), codeHash)
+	require.Equal(t, profile.Phone, phone)
+	_, _, err = r.UsePhoneVerificationAttempt(ctx, profile.ID)
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+
+	require.ErrorIs(t, r.ConfirmPhone(ctx, profile.ID, "+375297654321"), pgx.ErrNoRows)
+	require.NoError(t, r.ConfirmPhone(ctx, profile.ID, profile.Phone))
+}
+
+func TestWebAuthnSignCount(t *testing.T) {
+	r := openRepository(t)
+	profile := repotest.NewProfile()
+	ctx := context.Background()
+	require.NoError(t, r.CreateProfile(ctx, profile))
+	credential := &model.WebAuthnCredential{ID: []byte("credential"), ProfileID: profile.ID, SignCount: 5, Transports: []string{"usb", "nfc"}}
+	_, err := r.AddWebAuthnCredential(ctx, credential, nil)
+	require.NoError(t, err)
+	_, err = r.AddWebAuthnCredential(ctx, credential, nil)
+	require.Error(t, err)
+
+	require.ErrorIs(t, r.UpdateWebAuthnSignCount(ctx, profile.ID, credential.ID, 5), pgx.ErrNoRows)
+	require.NoError(t, r.UpdateWebAuthnSignCount(ctx, profile.ID, credential.ID, 6))
+	credentials, err := r.GetWebAuthnCredentials(ctx, profile.ID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	require.Equal(t, uint32(6), credentials[0].SignCount)
+	require.Equal(t, []string{"usb", "nfc"}, credentials[0].Transports)
+	require.NotNil(t, credentials[0].LastUsedAt)
+
+	require.NoError(t, r.AddWebAuthnSession(ctx, profile.ID, "login", []byte("session"), time.Now().Add(time.Minute)))
+	data, err := r.PopWebAuthnSession(ctx, profile.ID, "login")
+	require.NoError(t, err)
+	require.Equal(t, []byte("session"), data)
+	_, err = r.PopWebAuthnSession(ctx, profile.ID, "login")
+	require.ErrorIs(t, err, pgxv5.ErrNoRows)
+}
+
+func TestProfileChanges(t *testing.T) {
+	r := openRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready := make(chan int64, 1)
+	notified := make(chan *model.ProfileChange, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Listen(ctx, func(latest int64) { ready <- latest }, func(change *model.ProfileChange) { notified <- change })
+	}()
+	require.Zero(t, <-ready)
+
+	profile := repotest.NewProfile()
+	require.NoError(t, r.CreateProfile(context.Background(), profile))
+	require.NoError(t, r.AddRefreshToken(context.Background(), []byte("other"), profile.ID))
+	require.NoError(t, r.UpdateEmail(context.Background(), profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.UpdateEmail(context.Background(), profile.ID, "vladimir@example.com"))
+	require.NoError(t, r.DeleteProfile(context.Background(), profile.ID))
+
+	for i, operation := range []string{"INSERT", "UPDATE", "DELETE"} {
+		change := <-notified
+		require.Equal(t, int64(i+1), change.Sequence)
+		require.Equal(t, operation, change.Operation)
+		require.Equal(t, profile.ID, change.ProfileID)
+	}
+	changes, err := r.GetProfileChanges(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, int64(2), changes[0].Sequence)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.NoError(t, r.DeleteChanges(context.Background(), -time.Second))
+	horizon, err := r.GetProfileChangesHorizon(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), horizon)
+	changes, err = r.GetProfileChanges(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// sequences aren't reused after old changes are deleted
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		done <- r.Listen(ctx, func(latest int64) { ready <- latest }, func(*model.ProfileChange) {})
+	}()
+	require.Equal(t, int64(3), <-ready)
+}
@@ -0,0 +1,96 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// ReplaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead
+func (r *ProfileRepository) ReplaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error {
+	if err := duplicatedRecoveryCode(codeHashes); err != nil {
+		return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> %w", err)
+	}
+	return r.write(ctx, "ReplaceRecoveryCodes", func(tx *writeTx) error {
+		if err := profileExists(ctx, tx, profileID); err != nil {
+			return err
+		}
+		if err := r.replaceRecoveryCodes(ctx, tx, profileID, codeHashes); err != nil {
+			return fmt.Errorf("ProfileRepository -> ReplaceRecoveryCodes -> %w", err)
+		}
+		return nil
+	})
+}
+
+// duplicatedRecoveryCode returns error if some of codeHashes is repeated
+func duplicatedRecoveryCode(codeHashes [][]byte) error {
+	for i, codeHash := range codeHashes {
+		for _, previous := range codeHashes[:i] {
+			if bytes.Equal(previous, codeHash) {
+				return fmt.Errorf("error: recovery code is duplicated")
+			}
+		}
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes all recovery codes of the profile and adds the given hashes instead within tx
+func (r *ProfileRepository) replaceRecoveryCodes(ctx context.Context, tx *writeTx, profileID uuid.UUID, codeHashes [][]byte) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM recovery_codes WHERE profile_id = ?", profileID)
+	if err != nil {
+		return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+	}
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, "INSERT INTO recovery_codes (profile_id, code_hash) VALUES(?, ?)", profileID, codeHash)
+		if err != nil {
+			return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+		}
+	}
+	err = r.recordAudit(ctx, tx, "ReplaceRecoveryCodes", profileID, map[string]interface{}{"count": len(codeHashes)})
+	if err != nil {
+		return fmt.Errorf("replaceRecoveryCodes -> %w", err)
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode marks unused recovery code of the profile as used and returns the number of codes left
+func (r *ProfileRepository) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, codeHash []byte) (codesLeft int32, err error) {
+	err = r.write(ctx, "ConsumeRecoveryCode", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE recovery_codes SET used_at = ? WHERE profile_id = ? AND code_hash = ? AND used_at IS NULL",
+			nanos(r.now()), profileID, codeHash)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode: %w", err)
+		}
+		if err = affected(res); err != nil {
+			return err
+		}
+		err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM recovery_codes WHERE profile_id = ? AND used_at IS NULL", profileID).Scan(&codesLeft)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode -> QueryRow: %w", err)
+		}
+		err = r.recordAudit(ctx, tx, "ConsumeRecoveryCode", profileID, map[string]interface{}{"remaining": codesLeft})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> ConsumeRecoveryCode -> %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return codesLeft, nil
+}
+
+// profileExists returns pgx ErrNoRows if there is no profile with the id
+func profileExists(ctx context.Context, tx *writeTx, id uuid.UUID) error {
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM profiles WHERE id = ?", id).Scan(&count); err != nil {
+		return fmt.Errorf("profileExists -> %w", err)
+	}
+	if count == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// AssignRole grants existing role to the profile, assigning the role twice isn't an error and isn't audited
+func (r *ProfileRepository) AssignRole(ctx context.Context, id uuid.UUID, role string) error {
+	return r.write(ctx, "AssignRole", func(tx *writeTx) error {
+		var profiles, roles int
+		err := tx.QueryRowContext(ctx, "SELECT (SELECT COUNT(*) FROM profiles WHERE id = ?), (SELECT COUNT(*) FROM roles WHERE name = ?)",
+			id, role).Scan(&profiles, &roles)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AssignRole -> %w", err)
+		}
+		if profiles == 0 || roles == 0 {
+			return pgx.ErrNoRows
+		}
+		res, err := tx.ExecContext(ctx, "INSERT INTO profile_roles (profile_id, role, granted_at) VALUES(?, ?, ?) ON CONFLICT DO NOTHING",
+			id, role, nanos(r.now()))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AssignRole -> %w", err)
+		}
+		if affected(res) != nil {
+			return nil
+		}
+		if err = r.recordAudit(ctx, tx, "AssignRole", id, map[string]interface{}{"role": role}); err != nil {
+			return fmt.Errorf("ProfileRepository -> AssignRole -> %w", err)
+		}
+		return nil
+	})
+}
+
+// RevokeRole takes the role away from the profile
+func (r *ProfileRepository) RevokeRole(ctx context.Context, id uuid.UUID, role string) error {
+	return r.write(ctx, "RevokeRole", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM profile_roles WHERE profile_id = ? AND role = ?", id, role)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> RevokeRole -> %w", err)
+		}
+		if err = affected(res); err != nil {
+			return err
+		}
+		if err = r.recordAudit(ctx, tx, "RevokeRole", id, map[string]interface{}{"role": role}); err != nil {
+			return fmt.Errorf("ProfileRepository -> RevokeRole -> %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRoles returns names of roles assigned to the profile
+func (r *ProfileRepository) GetRoles(ctx context.Context, id uuid.UUID) ([]string, error) {
+	return r.queryStrings(ctx, "GetRoles", "SELECT role FROM profile_roles WHERE profile_id = ? ORDER BY role", id)
+}
+
+// GetPermissions returns distinct permissions of all roles of the profile
+func (r *ProfileRepository) GetPermissions(ctx context.Context, id uuid.UUID) ([]string, error) {
+	return r.queryStrings(ctx, "GetPermissions", `SELECT DISTINCT rp.permission FROM profile_roles pr
+		JOIN role_permissions rp ON rp.role = pr.role WHERE pr.profile_id = ? ORDER BY rp.permission`, id)
+}
+
+// queryStrings returns values of the single column selected by the query
+func (r *ProfileRepository) queryStrings(ctx context.Context, method, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+		}
+		values = append(values, value)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> %s -> %w", method, err)
+	}
+	return values, nil
+}
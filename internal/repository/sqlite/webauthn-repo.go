@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+// AddWebAuthnSession saves state of the WebAuthn ceremony of the profile instead of the previous one
+func (r *ProfileRepository) AddWebAuthnSession(ctx context.Context, id uuid.UUID, ceremony string, sessionData []byte, expiresAt time.Time) error {
+	return r.write(ctx, "AddWebAuthnSession", func(tx *writeTx) error {
+		if err := profileExists(ctx, tx, id); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO webauthn_sessions (profile_id, ceremony, session_data, expires_at) VALUES(?, ?, ?, ?)
+			ON CONFLICT (profile_id, ceremony) DO UPDATE SET session_data = excluded.session_data, expires_at = excluded.expires_at`,
+			id, ceremony, sessionData, nanos(expiresAt))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnSession -> %w", err)
+		}
+		return nil
+	})
+}
+
+// PopWebAuthnSession deletes unexpired state of the WebAuthn ceremony of the profile and returns it
+func (r *ProfileRepository) PopWebAuthnSession(ctx context.Context, id uuid.UUID, ceremony string) (sessionData []byte, err error) {
+	err = r.write(ctx, "PopWebAuthnSession", func(tx *writeTx) error {
+		var expiresAt int64
+		err := tx.QueryRowContext(ctx, "DELETE FROM webauthn_sessions WHERE profile_id = ? AND ceremony = ? RETURNING session_data, expires_at",
+			id, ceremony).Scan(&sessionData, &expiresAt)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> PopWebAuthnSession -> %w", noRows(err))
+		}
+		if expiresAt <= nanos(r.now()) {
+			// expired session is deleted anyway, it can't be used later
+			return fmt.Errorf("ProfileRepository -> PopWebAuthnSession -> %w", noRows(sql.ErrNoRows))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionData, nil
+}
+
+// AddWebAuthnCredential inserts model.WebAuthnCredential into webauthn_credentials table and fills its CreatedAt.
+// If it's the first credential of the profile and the profile has no unused recovery codes, recoveryCodeHashes
+// replace its recovery codes in the same transaction
+func (r *ProfileRepository) AddWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredential,
+	recoveryCodeHashes [][]byte) (recoveryCodesStored bool, err error) {
+	transports, err := json.Marshal(credential.Transports)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	if err = duplicatedRecoveryCode(recoveryCodeHashes); err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	err = r.write(ctx, "AddWebAuthnCredential", func(tx *writeTx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM webauthn_credentials WHERE id = ?", credential.ID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> QueryRow -> error: credential with such id already exists")
+		}
+		if err = profileExists(ctx, tx, credential.ProfileID); err != nil {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> error: profile doesn't exist")
+		}
+		if len(recoveryCodeHashes) > 0 {
+			err = tx.QueryRowContext(ctx, `SELECT NOT EXISTS (SELECT 1 FROM webauthn_credentials WHERE profile_id = ?)
+				AND NOT EXISTS (SELECT 1 FROM recovery_codes WHERE profile_id = ? AND used_at IS NULL)`,
+				credential.ProfileID, credential.ProfileID).Scan(&recoveryCodesStored)
+			if err != nil {
+				return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+			}
+		}
+
+		createdAt := r.now()
+		_, err = tx.ExecContext(ctx, `INSERT INTO webauthn_credentials (id, profile_id, public_key, sign_count, transports, aaguid, attestation_type, created_at)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?)`, credential.ID, credential.ProfileID, credential.PublicKey, credential.SignCount,
+			string(transports), credential.AAGUID, credential.AttestationType, nanos(createdAt))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+		err = r.recordAudit(ctx, tx, "AddWebAuthnCredential", credential.ProfileID, map[string]interface{}{
+			"credentialId": credential.ID, "publicKey": credential.PublicKey, "transports": credential.Transports,
+			"aaguid": credential.AAGUID, "attestationType": credential.AttestationType,
+		})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+		if recoveryCodesStored {
+			if err = r.replaceRecoveryCodes(ctx, tx, credential.ProfileID, recoveryCodeHashes); err != nil {
+				return fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+			}
+		}
+		credential.CreatedAt = createdAt
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return recoveryCodesStored, nil
+}
+
+// GetWebAuthnCredentials returns all WebAuthn credentials of the profile in the order they were added
+func (r *ProfileRepository) GetWebAuthnCredentials(ctx context.Context, id uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, profile_id, public_key, sign_count, transports, aaguid, attestation_type, created_at, last_used_at
+		FROM webauthn_credentials WHERE profile_id = ? ORDER BY created_at, rowid`, id)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+	}
+	defer rows.Close()
+
+	var credentials []*model.WebAuthnCredential
+	for rows.Next() {
+		var (
+			credential model.WebAuthnCredential
+			transports string
+			createdAt  int64
+			lastUsedAt sql.NullInt64
+		)
+		err = rows.Scan(&credential.ID, &credential.ProfileID, &credential.PublicKey, &credential.SignCount, &transports,
+			&credential.AAGUID, &credential.AttestationType, &createdAt, &lastUsedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+		}
+		if err = json.NewDecoder(strings.NewReader(transports)).Decode(&credential.Transports); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+		}
+		credential.CreatedAt = time.Unix(0, createdAt)
+		credential.LastUsedAt = toTime(lastUsedAt)
+		credentials = append(credentials, &credential)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+	}
+	return credentials, nil
+}
+
+// UpdateWebAuthnSignCount sets new sign count of the credential only if it grows, so replayed or cloned
+// authenticator can't move the counter back
+func (r *ProfileRepository) UpdateWebAuthnSignCount(ctx context.Context, id uuid.UUID, credentialID []byte, signCount uint32) error {
+	return r.write(ctx, "UpdateWebAuthnSignCount", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE webauthn_credentials SET sign_count = ?1, last_used_at = ?2
+			WHERE profile_id = ?3 AND id = ?4 AND (sign_count < ?1 OR sign_count = 0 AND ?1 = 0)`,
+			signCount, nanos(r.now()), id, credentialID)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> UpdateWebAuthnSignCount -> %w", err)
+		}
+		return affected(res)
+	})
+}
+
+// DeleteWebAuthnCredential deletes exact credential of the profile
+func (r *ProfileRepository) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID, credentialID []byte) error {
+	return r.write(ctx, "DeleteWebAuthnCredential", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM webauthn_credentials WHERE profile_id = ? AND id = ?", id, credentialID)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> %w", err)
+		}
+		if err = affected(res); err != nil {
+			return err
+		}
+		err = r.recordAudit(ctx, tx, "DeleteWebAuthnCredential", id, map[string]interface{}{"credentialId": credentialID})
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> %w", err)
+		}
+		return nil
+	})
+}
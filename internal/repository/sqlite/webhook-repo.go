@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+// CreateWebhookSubscription inserts model.WebhookSubscription into webhook_subscriptions table and fills its CreatedAt
+func (r *ProfileRepository) CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription -> %w", err)
+	}
+	return r.write(ctx, "CreateWebhookSubscription", func(tx *writeTx) error {
+		var count int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_subscriptions WHERE id = ?", subscription.ID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription -> %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription -> error: subscription with such id already exists")
+		}
+		createdAt := r.now()
+		_, err = tx.ExecContext(ctx, "INSERT INTO webhook_subscriptions (id, url, event_types, secret, created_at) VALUES(?, ?, ?, ?, ?)",
+			subscription.ID, subscription.URL, string(eventTypes), subscription.Secret, nanos(createdAt))
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription -> %w", err)
+		}
+		subscription.CreatedAt = createdAt
+		return nil
+	})
+}
+
+// GetWebhookSubscriptions returns all webhook subscriptions without their secrets in the order they were created
+func (r *ProfileRepository) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, url, event_types, created_at FROM webhook_subscriptions ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*model.WebhookSubscription{}
+	for rows.Next() {
+		var (
+			subscription model.WebhookSubscription
+			eventTypes   string
+			createdAt    int64
+		)
+		if err = rows.Scan(&subscription.ID, &subscription.URL, &eventTypes, &createdAt); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+		}
+		if err = json.Unmarshal([]byte(eventTypes), &subscription.EventTypes); err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+		}
+		subscription.CreatedAt = time.Unix(0, createdAt)
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription deletes the subscription
+func (r *ProfileRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	return r.write(ctx, "DeleteWebhookSubscription", func(tx *writeTx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM webhook_subscriptions WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("ProfileRepository -> DeleteWebhookSubscription: %w", err)
+		}
+		return affected(res)
+	})
+}
+
+// GetWebhookAttempts returns no attempts because events of the SQLite repository are never delivered
+func (r *ProfileRepository) GetWebhookAttempts(context.Context, uuid.UUID, int32) ([]*model.WebhookAttempt, error) {
+	return []*model.WebhookAttempt{}, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+)
+
+// AddWebAuthnSession saves state of the WebAuthn ceremony of the profile instead of the previous one
+func (r *ProfileRepository) AddWebAuthnSession(ctx context.Context, id uuid.UUID, ceremony string, sessionData []byte, expiresAt time.Time) error {
+	res, err := r.pool.Exec(ctx, `INSERT INTO webauthn_sessions (profile_id, ceremony, session_data, expires_at)
+		SELECT id, $1, $2, $3 FROM profiles WHERE id = $4
+		ON CONFLICT (profile_id, ceremony) DO UPDATE SET session_data = EXCLUDED.session_data, expires_at = EXCLUDED.expires_at`,
+		ceremony, sessionData, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> AddWebAuthnSession -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// PopWebAuthnSession deletes unexpired state of the WebAuthn ceremony of the profile and returns it
+func (r *ProfileRepository) PopWebAuthnSession(ctx context.Context, id uuid.UUID, ceremony string) (sessionData []byte, err error) {
+	err = r.pool.QueryRow(ctx, `DELETE FROM webauthn_sessions WHERE profile_id = $1 AND ceremony = $2 AND expires_at > now()
+		RETURNING session_data`, id, ceremony).Scan(&sessionData)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> PopWebAuthnSession -> %w", err)
+	}
+	return sessionData, nil
+}
+
+// AddWebAuthnCredential creates the row in webauthn_credentials table with fields of model.WebAuthnCredential and fills its CreatedAt.
+// If it's the first credential of the profile and the profile has no unused recovery codes, recoveryCodeHashes replace
+// its recovery codes in the same transaction. The profile row is locked, so only one of concurrent first credentials stores them
+func (r *ProfileRepository) AddWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredential,
+	recoveryCodeHashes [][]byte) (recoveryCodesStored bool, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, "SELECT id FROM profiles WHERE id = $1 FOR UPDATE", credential.ProfileID)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	var count int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM webauthn_credentials WHERE id = $1", credential.ID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	if count > 0 {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> QueryRow -> error: credential with such id already exists")
+	}
+	if len(recoveryCodeHashes) > 0 {
+		err = tx.QueryRow(ctx, `SELECT NOT EXISTS (SELECT 1 FROM webauthn_credentials WHERE profile_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM recovery_codes WHERE profile_id = $1 AND used_at IS NULL)`,
+			credential.ProfileID).Scan(&recoveryCodesStored)
+		if err != nil {
+			return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+	}
+
+	err = tx.QueryRow(ctx, `INSERT INTO webauthn_credentials (id, profile_id, public_key, sign_count, transports, aaguid, attestation_type)
+		VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`, credential.ID, credential.ProfileID, credential.PublicKey,
+		int64(credential.SignCount), credential.Transports, credential.AAGUID, credential.AttestationType).Scan(&credential.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	err = recordAudit(ctx, tx, "AddWebAuthnCredential", credential.ProfileID, map[string]interface{}{
+		"credentialId": credential.ID, "publicKey": credential.PublicKey, "transports": credential.Transports,
+		"aaguid": credential.AAGUID, "attestationType": credential.AttestationType,
+	})
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+	}
+	if recoveryCodesStored {
+		err = replaceRecoveryCodes(ctx, tx, credential.ProfileID, recoveryCodeHashes)
+		if err != nil {
+			return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ProfileRepository -> AddWebAuthnCredential -> Commit: %w", err)
+	}
+	return recoveryCodesStored, nil
+}
+
+// GetWebAuthnCredentials returns all WebAuthn credentials of the profile
+func (r *ProfileRepository) GetWebAuthnCredentials(ctx context.Context, id uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, public_key, sign_count, transports, aaguid, attestation_type, created_at, last_used_at
+		FROM webauthn_credentials WHERE profile_id = $1 ORDER BY created_at`, id)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+	}
+	defer rows.Close()
+
+	var credentials []*model.WebAuthnCredential
+	for rows.Next() {
+		credential := model.WebAuthnCredential{ProfileID: id}
+		var signCount int64
+		err = rows.Scan(&credential.ID, &credential.PublicKey, &signCount, &credential.Transports, &credential.AAGUID,
+			&credential.AttestationType, &credential.CreatedAt, &credential.LastUsedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+		}
+		credential.SignCount = uint32(signCount)
+		credentials = append(credentials, &credential)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebAuthnCredentials -> %w", err)
+	}
+	return credentials, nil
+}
+
+// UpdateWebAuthnSignCount sets new sign count of the credential only if it grows, so replayed or cloned
+// authenticator can't move the counter back
+func (r *ProfileRepository) UpdateWebAuthnSignCount(ctx context.Context, id uuid.UUID, credentialID []byte, signCount uint32) error {
+	res, err := r.pool.Exec(ctx, `UPDATE webauthn_credentials SET sign_count = $1, last_used_at = now()
+		WHERE profile_id = $2 AND id = $3 AND (sign_count < $1 OR (sign_count = 0 AND $1 = 0))`, int64(signCount), id, credentialID)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> UpdateWebAuthnSignCount -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteWebAuthnCredential deletes exact credential of the profile from webauthn_credentials table
+func (r *ProfileRepository) DeleteWebAuthnCredential(ctx context.Context, id uuid.UUID, credentialID []byte) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	res, err := tx.Exec(ctx, "DELETE FROM webauthn_credentials WHERE profile_id = $1 AND id = $2", id, credentialID)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	err = recordAudit(ctx, tx, "DeleteWebAuthnCredential", id, map[string]interface{}{"credentialId": credentialID})
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteWebAuthnCredential -> Commit: %w", err)
+	}
+	return nil
+}
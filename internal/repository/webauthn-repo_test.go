@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnSession(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Venedikt"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	err = r.AddWebAuthnSession(context.Background(), testProfile.ID, "login", []byte("first"), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	err = r.AddWebAuthnSession(context.Background(), testProfile.ID, "login", []byte("second"), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	sessionData, err := r.PopWebAuthnSession(context.Background(), testProfile.ID, "login")
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), sessionData)
+	_, err = r.PopWebAuthnSession(context.Background(), testProfile.ID, "login")
+	require.Error(t, err)
+}
+
+func TestWebAuthnCredential(t *testing.T) {
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vikentiy"
+	err := r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+
+	credential := model.WebAuthnCredential{
+		ID:              []byte("credential"),
+		ProfileID:       testProfile.ID,
+		PublicKey:       []byte("publicKey"),
+		SignCount:       5,
+		Transports:      []string{"usb", "nfc"},
+		AAGUID:          make([]byte, 16),
+		AttestationType: "none",
+	}
+	_, err = r.AddWebAuthnCredential(context.Background(), &credential, nil)
+	require.NoError(t, err)
+	require.False(t, credential.CreatedAt.IsZero())
+	_, err = r.AddWebAuthnCredential(context.Background(), &credential, nil)
+	require.Error(t, err)
+
+	err = r.UpdateWebAuthnSignCount(context.Background(), testProfile.ID, credential.ID, 5)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	err = r.UpdateWebAuthnSignCount(context.Background(), testProfile.ID, credential.ID, 6)
+	require.NoError(t, err)
+
+	credentials, err := r.GetWebAuthnCredentials(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	require.Equal(t, uint32(6), credentials[0].SignCount)
+	require.Equal(t, credential.Transports, credentials[0].Transports)
+	require.NotNil(t, credentials[0].LastUsedAt)
+
+	err = r.DeleteWebAuthnCredential(context.Background(), testProfile.ID, credential.ID)
+	require.NoError(t, err)
+	err = r.DeleteWebAuthnCredential(context.Background(), testProfile.ID, credential.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateWebhookSubscription creates the row in webhook_subscriptions table with fields of model.WebhookSubscription and fills its CreatedAt
+func (r *ProfileRepository) CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	err := r.pool.QueryRow(ctx, `INSERT INTO webhook_subscriptions (id, url, event_types, secret) VALUES($1, $2, $3, $4)
+		RETURNING created_at`, subscription.ID, subscription.URL, subscription.EventTypes, subscription.Secret).Scan(&subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> CreateWebhookSubscription: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookSubscriptions returns all webhook subscriptions without their secrets
+func (r *ProfileRepository) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	rows, err := r.reader(ctx).Query(ctx, "SELECT id, url, event_types, created_at FROM webhook_subscriptions ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*model.WebhookSubscription{}
+	for rows.Next() {
+		var subscription model.WebhookSubscription
+		err = rows.Scan(&subscription.ID, &subscription.URL, &subscription.EventTypes, &subscription.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookSubscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription deletes the subscription with its pending deliveries and attempts
+func (r *ProfileRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	res, err := r.pool.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ProfileRepository -> DeleteWebhookSubscription: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetWebhookAttempts returns up to limit latest delivery attempts of the subscription from the newest to the oldest
+func (r *ProfileRepository) GetWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error) {
+	rows, err := r.reader(ctx).Query(ctx, `SELECT a.id, a.delivery_id, d.event_id, d.event_type, a.status_code, a.error, a.duration_ms, a.attempted_at
+		FROM webhook_attempts a JOIN webhook_deliveries d ON d.id = a.delivery_id
+		WHERE d.subscription_id = $1 ORDER BY a.id DESC LIMIT $2`, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookAttempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []*model.WebhookAttempt{}
+	for rows.Next() {
+		var attempt model.WebhookAttempt
+		var durationMS int64
+		err = rows.Scan(&attempt.ID, &attempt.DeliveryID, &attempt.EventID, &attempt.EventType, &attempt.StatusCode, &attempt.Error,
+			&durationMS, &attempt.AttemptedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ProfileRepository -> GetWebhookAttempts: %w", err)
+		}
+		attempt.Duration = time.Duration(durationMS) * time.Millisecond
+		attempts = append(attempts, &attempt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProfileRepository -> GetWebhookAttempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// WebhookRepository contains pgxpool
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates an object of *WebhookRepository
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// ClaimDeliveries returns up to limit deliveries which attempt is due and postpones their next attempt by lease,
+// so other replicas don't send them while the attempt is in progress
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, `UPDATE webhook_deliveries d SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhook_subscriptions s WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id FROM webhook_deliveries WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id LIMIT $1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.subscription_id, s.url, s.secret, d.event_id, d.event_type, d.profile_id, d.payload, d.occurred_at, d.attempts`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("WebhookRepository -> ClaimDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		var d model.WebhookDelivery
+		err = rows.Scan(&d.ID, &d.SubscriptionID, &d.URL, &d.Secret, &d.Event.ID, &d.Event.Type, &d.Event.ProfileID, &d.Event.Payload,
+			&d.Event.CreatedAt, &d.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("WebhookRepository -> ClaimDeliveries: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebhookRepository -> ClaimDeliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt saves the attempt and marks its delivery as delivered, failed if nextAttemptAt is zero,
+// or due at nextAttemptAt otherwise
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, attempt *model.WebhookAttempt, delivered bool, nextAttemptAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("WebhookRepository -> RecordAttempt -> Begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, err = tx.Exec(ctx, `INSERT INTO webhook_attempts (delivery_id, status_code, error, duration_ms, attempted_at)
+		VALUES($1, $2, $3, $4, $5)`, attempt.DeliveryID, attempt.StatusCode, attempt.Error, attempt.Duration.Milliseconds(), attempt.AttemptedAt)
+	if err != nil {
+		return fmt.Errorf("WebhookRepository -> RecordAttempt -> %w", err)
+	}
+	var next interface{}
+	if !nextAttemptAt.IsZero() {
+		next = nextAttemptAt
+	}
+	res, err := tx.Exec(ctx, `UPDATE webhook_deliveries SET attempts = attempts + 1,
+		delivered_at = CASE WHEN $2 THEN now() END,
+		failed_at = CASE WHEN NOT $2 AND $3::timestamptz IS NULL THEN now() END,
+		next_attempt_at = COALESCE($3, next_attempt_at) WHERE id = $1`, attempt.DeliveryID, delivered, next)
+	if err != nil {
+		return fmt.Errorf("WebhookRepository -> RecordAttempt -> %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("WebhookRepository -> RecordAttempt -> Commit: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeliveries deletes deliveries that were delivered or failed longer than age ago together with their attempts,
+// pending deliveries are kept however old they are
+func (r *WebhookRepository) DeleteDeliveries(ctx context.Context, age time.Duration) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM webhook_deliveries
+		WHERE COALESCE(delivered_at, failed_at) < now() - make_interval(secs => $1)`, age.Seconds())
+	if err != nil {
+		return fmt.Errorf("WebhookRepository -> DeleteDeliveries -> %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	w := NewWebhookRepository(r.pool)
+	subscription := model.WebhookSubscription{ID: uuid.New(), URL: "https://partner.example.com/hooks",
+		EventTypes: []string{model.EventProfileDeleted}, Secret: "secret"}
+	err := r.CreateWebhookSubscription(context.Background(), &subscription)
+	require.NoError(t, err)
+
+	testProfile.ID = uuid.New()
+	testProfile.Username = "Vitaliy"
+	err = r.CreateProfile(context.Background(), &testProfile)
+	require.NoError(t, err)
+	err = r.DeleteProfile(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+
+	deliveries, err := w.ClaimDeliveries(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, subscription.ID, deliveries[0].SubscriptionID)
+	require.Equal(t, "secret", deliveries[0].Secret)
+	require.Equal(t, model.EventProfileDeleted, deliveries[0].Event.Type)
+	require.Equal(t, testProfile.ID, deliveries[0].Event.ProfileID)
+
+	deliveries2, err := w.ClaimDeliveries(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, deliveries2)
+
+	attempt := model.WebhookAttempt{DeliveryID: deliveries[0].ID, StatusCode: 503, Error: "unexpected status",
+		Duration: 20 * time.Millisecond, AttemptedAt: time.Now()}
+	err = w.RecordAttempt(context.Background(), &attempt, false, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	deliveries, err = w.ClaimDeliveries(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+
+	attempt.StatusCode = 200
+	attempt.Error = ""
+	err = w.RecordAttempt(context.Background(), &attempt, true, time.Time{})
+	require.NoError(t, err)
+
+	attempts, err := r.GetWebhookAttempts(context.Background(), subscription.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	require.Equal(t, int32(200), attempts[0].StatusCode)
+	require.Equal(t, int32(503), attempts[1].StatusCode)
+
+	err = w.DeleteDeliveries(context.Background(), time.Hour)
+	require.NoError(t, err)
+	attempts, err = r.GetWebhookAttempts(context.Background(), subscription.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	err = w.DeleteDeliveries(context.Background(), 0)
+	require.NoError(t, err)
+	attempts, err = r.GetWebhookAttempts(context.Background(), subscription.ID, 10)
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+
+	err = r.DeleteWebhookSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+	err = r.DeleteWebhookSubscription(context.Background(), subscription.ID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
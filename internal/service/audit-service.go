@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/model"
+)
+
+// DefaultAuditEventsLimit is the number of audit events returned when the filter doesn't set it
+const DefaultAuditEventsLimit = 100
+
+// ListAuditEvents calls lower method of ProfileRepository ListAuditEvents with default limit if it's not set
+func (s *ProfileService) ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditEventsLimit
+	}
+	events, err := s.r.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> ListAuditEvents -> %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuditEvents(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	events := []*model.AuditEvent{{ID: 2, Action: "DeleteProfile", TargetID: testProfile.ID}}
+	r.On("ListAuditEvents", mock.Anything, &model.AuditFilter{TargetID: testProfile.ID, Limit: DefaultAuditEventsLimit}).
+		Return(events, nil)
+
+	s := NewProfileService(r, &cfg)
+
+	readEvents, err := s.ListAuditEvents(context.Background(), &model.AuditFilter{TargetID: testProfile.ID})
+	require.NoError(t, err)
+	require.Equal(t, events, readEvents)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetPasswordAndIDByEmail calls lower method of ProfileRepository GetPasswordAndIDByEmail with normalized email
+func (s *ProfileService) GetPasswordAndIDByEmail(ctx context.Context, email string) (profileID uuid.UUID, password []byte, err error) {
+	profileID, hashedPassword, err := s.r.GetPasswordAndIDByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileService -> GetPasswordAndIDByEmail -> %w", err)
+	}
+	return profileID, hashedPassword, nil
+}
+
+// UpdateEmail normalizes email and sets it to the profile, email becomes unverified if it was changed
+func (s *ProfileService) UpdateEmail(ctx context.Context, profileID uuid.UUID, email string) error {
+	err := s.r.UpdateEmail(ctx, profileID, normalizeEmail(email))
+	if err != nil {
+		return fmt.Errorf("ProfileService -> UpdateEmail -> %w", err)
+	}
+	return nil
+}
+
+// IssueEmailVerificationToken generates verification token for current email of the profile, stores its hash
+// and returns plain token that should be sent to the user
+func (s *ProfileService) IssueEmailVerificationToken(ctx context.Context, profileID uuid.UUID) (token string, expiresAt time.Time, err error) {
+	token, err = randomToken()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssueEmailVerificationToken -> %w", err)
+	}
+	expiresAt = time.Now().Add(s.cfg.EmailVerificationTTL)
+	err = s.r.AddEmailVerificationToken(ctx, profileID, s.hashSecret(token), expiresAt)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssueEmailVerificationToken -> %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ConfirmEmail marks email of the profile as verified by token and returns id of that profile
+func (s *ProfileService) ConfirmEmail(ctx context.Context, token string) (profileID uuid.UUID, err error) {
+	profileID, err = s.r.ConfirmEmail(ctx, s.hashSecret(token))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileService -> ConfirmEmail -> %w", err)
+	}
+	return profileID, nil
+}
+
+// normalizeEmail trims spaces and lowers case of email so the same address can't be stored twice
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
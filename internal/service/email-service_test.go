@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPasswordAndIDByEmail(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetPasswordAndIDByEmail", mock.Anything, "volodya@example.com").Return(testProfile.ID, []byte("pass"), nil)
+
+	s := NewProfileService(r, &cfg)
+
+	profileID, _, err := s.GetPasswordAndIDByEmail(context.Background(), " Volodya@Example.com")
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID, profileID)
+}
+
+func TestUpdateEmail(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("UpdateEmail", mock.Anything, testProfile.ID, "volodya@example.com").Return(nil)
+
+	s := NewProfileService(r, &cfg)
+
+	err := s.UpdateEmail(context.Background(), testProfile.ID, "VOLODYA@example.com ")
+	require.NoError(t, err)
+}
+
+func TestIssueEmailVerificationToken(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	var storedHash []byte
+	r.On("AddEmailVerificationToken", mock.Anything, testProfile.ID, mock.AnythingOfType("[]uint8"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			storedHash = args.Get(2).([]byte)
+		}).Return(nil)
+
+	testCfg := cfg
+	testCfg.EmailVerificationTTL = time.Hour
+	s := NewProfileService(r, &testCfg)
+
+	token, expiresAt, err := s.IssueEmailVerificationToken(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Equal(t, s.hashSecret(token), storedHash)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+}
+
+func TestConfirmEmail(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	s := NewProfileService(r, &cfg)
+	r.On("ConfirmEmail", mock.Anything, s.hashSecret("token")).Return(testProfile.ID, nil)
+
+	profileID, err := s.ConfirmEmail(context.Background(), "token")
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID, profileID)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueLoginToken generates single-use passwordless login token for the profile, stores its hash
+// and returns plain token that should be sent to the user as a magic link
+func (s *ProfileService) IssueLoginToken(ctx context.Context, profileID uuid.UUID) (token string, expiresAt time.Time, err error) {
+	token, err = randomToken()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssueLoginToken -> %w", err)
+	}
+	expiresAt = time.Now().Add(s.cfg.LoginTokenTTL)
+	err = s.r.AddLoginToken(ctx, profileID, s.hashSecret(token), expiresAt)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssueLoginToken -> %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// RedeemLoginToken consumes login token and returns id of the profile it was issued for
+func (s *ProfileService) RedeemLoginToken(ctx context.Context, token string) (profileID uuid.UUID, err error) {
+	profileID, err = s.r.RedeemLoginToken(ctx, s.hashSecret(token))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("ProfileService -> RedeemLoginToken -> %w", err)
+	}
+	return profileID, nil
+}
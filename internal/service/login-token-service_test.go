@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueLoginToken(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	var storedHash []byte
+	r.On("AddLoginToken", mock.Anything, testProfile.ID, mock.AnythingOfType("[]uint8"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			storedHash = args.Get(2).([]byte)
+		}).Return(nil)
+
+	testCfg := cfg
+	testCfg.LoginTokenTTL = 15 * time.Minute
+	s := NewProfileService(r, &testCfg)
+
+	token, expiresAt, err := s.IssueLoginToken(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Equal(t, s.hashSecret(token), storedHash)
+	require.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Minute)
+}
+
+func TestRedeemLoginToken(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	s := NewProfileService(r, &cfg)
+	r.On("RedeemLoginToken", mock.Anything, s.hashSecret("token")).Return(testProfile.ID, nil)
+
+	profileID, err := s.RedeemLoginToken(context.Background(), "token")
+	require.NoError(t, err)
+	require.Equal(t, testProfile.ID, profileID)
+}
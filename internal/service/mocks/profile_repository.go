@@ -8,6 +8,8 @@ import (
 	model "github.com/distuurbia/profile/internal/model"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,6 +18,48 @@ type ProfileRepository struct {
 	mock.Mock
 }
 
+// AddEmailVerificationToken provides a mock function with given fields: ctx, profileID, tokenHash, expiresAt
+func (_m *ProfileRepository) AddEmailVerificationToken(ctx context.Context, profileID uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	ret := _m.Called(ctx, profileID, tokenHash, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte, time.Time) error); ok {
+		r0 = rf(ctx, profileID, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddLoginToken provides a mock function with given fields: ctx, profileID, tokenHash, expiresAt
+func (_m *ProfileRepository) AddLoginToken(ctx context.Context, profileID uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	ret := _m.Called(ctx, profileID, tokenHash, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte, time.Time) error); ok {
+		r0 = rf(ctx, profileID, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddPhoneVerificationCode provides a mock function with given fields: ctx, profileID, codeHash, attempts, expiresAt
+func (_m *ProfileRepository) AddPhoneVerificationCode(ctx context.Context, profileID uuid.UUID, codeHash []byte, attempts int, expiresAt time.Time) error {
+	ret := _m.Called(ctx, profileID, codeHash, attempts, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte, int, time.Time) error); ok {
+		r0 = rf(ctx, profileID, codeHash, attempts, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddRefreshToken provides a mock function with given fields: ctx, refreshToken, profileID
 func (_m *ProfileRepository) AddRefreshToken(ctx context.Context, refreshToken []byte, profileID uuid.UUID) error {
 	ret := _m.Called(ctx, refreshToken, profileID)
@@ -30,6 +74,122 @@ func (_m *ProfileRepository) AddRefreshToken(ctx context.Context, refreshToken [
 	return r0
 }
 
+// AddWebAuthnCredential provides a mock function with given fields: ctx, credential, recoveryCodeHashes
+func (_m *ProfileRepository) AddWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredential, recoveryCodeHashes [][]byte) (bool, error) {
+	ret := _m.Called(ctx, credential, recoveryCodeHashes)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WebAuthnCredential, [][]byte) (bool, error)); ok {
+		return rf(ctx, credential, recoveryCodeHashes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WebAuthnCredential, [][]byte) bool); ok {
+		r0 = rf(ctx, credential, recoveryCodeHashes)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.WebAuthnCredential, [][]byte) error); ok {
+		r1 = rf(ctx, credential, recoveryCodeHashes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddWebAuthnSession provides a mock function with given fields: ctx, profileID, ceremony, sessionData, expiresAt
+func (_m *ProfileRepository) AddWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string, sessionData []byte, expiresAt time.Time) error {
+	ret := _m.Called(ctx, profileID, ceremony, sessionData, expiresAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, []byte, time.Time) error); ok {
+		r0 = rf(ctx, profileID, ceremony, sessionData, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AssignRole provides a mock function with given fields: ctx, profileID, role
+func (_m *ProfileRepository) AssignRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, profileID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmEmail provides a mock function with given fields: ctx, tokenHash
+func (_m *ProfileRepository) ConfirmEmail(ctx context.Context, tokenHash []byte) (uuid.UUID, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (uuid.UUID, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) uuid.UUID); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmPhone provides a mock function with given fields: ctx, profileID, phone
+func (_m *ProfileRepository) ConfirmPhone(ctx context.Context, profileID uuid.UUID, phone string) error {
+	ret := _m.Called(ctx, profileID, phone)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: ctx, profileID, codeHash
+func (_m *ProfileRepository) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, codeHash []byte) (int32, error) {
+	ret := _m.Called(ctx, profileID, codeHash)
+
+	var r0 int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) (int32, error)); ok {
+		return rf(ctx, profileID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) int32); ok {
+		r0 = rf(ctx, profileID, codeHash)
+	} else {
+		r0 = ret.Get(0).(int32)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r1 = rf(ctx, profileID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateProfile provides a mock function with given fields: ctx, profile
 func (_m *ProfileRepository) CreateProfile(ctx context.Context, profile *model.Profile) error {
 	ret := _m.Called(ctx, profile)
@@ -44,6 +204,20 @@ func (_m *ProfileRepository) CreateProfile(ctx context.Context, profile *model.P
 	return r0
 }
 
+// CreateWebhookSubscription provides a mock function with given fields: ctx, subscription
+func (_m *ProfileRepository) CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	ret := _m.Called(ctx, subscription)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WebhookSubscription) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteProfile provides a mock function with given fields: ctx, profileID
 func (_m *ProfileRepository) DeleteProfile(ctx context.Context, profileID uuid.UUID) error {
 	ret := _m.Called(ctx, profileID)
@@ -58,6 +232,104 @@ func (_m *ProfileRepository) DeleteProfile(ctx context.Context, profileID uuid.U
 	return r0
 }
 
+// DeleteWebAuthnCredential provides a mock function with given fields: ctx, profileID, credentialID
+func (_m *ProfileRepository) DeleteWebAuthnCredential(ctx context.Context, profileID uuid.UUID, credentialID []byte) error {
+	ret := _m.Called(ctx, profileID, credentialID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r0 = rf(ctx, profileID, credentialID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, subscriptionID
+func (_m *ProfileRepository) DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	ret := _m.Called(ctx, subscriptionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, subscriptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPasswordAndIDByEmail provides a mock function with given fields: ctx, email
+func (_m *ProfileRepository) GetPasswordAndIDByEmail(ctx context.Context, email string) (uuid.UUID, []byte, error) {
+	ret := _m.Called(ctx, email)
+
+	var r0 uuid.UUID
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, []byte, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, email)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPasswordAndIDByPhone provides a mock function with given fields: ctx, phone
+func (_m *ProfileRepository) GetPasswordAndIDByPhone(ctx context.Context, phone string) (uuid.UUID, []byte, error) {
+	ret := _m.Called(ctx, phone)
+
+	var r0 uuid.UUID
+	var r1 []byte
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uuid.UUID, []byte, error)); ok {
+		return rf(ctx, phone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uuid.UUID); ok {
+		r0 = rf(ctx, phone)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) []byte); ok {
+		r1 = rf(ctx, phone)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, phone)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetPasswordAndIDByUsername provides a mock function with given fields: ctx, username
 func (_m *ProfileRepository) GetPasswordAndIDByUsername(ctx context.Context, username string) (uuid.UUID, []byte, error) {
 	ret := _m.Called(ctx, username)
@@ -93,6 +365,108 @@ func (_m *ProfileRepository) GetPasswordAndIDByUsername(ctx context.Context, use
 	return r0, r1, r2
 }
 
+// GetPermissions provides a mock function with given fields: ctx, profileID
+func (_m *ProfileRepository) GetPermissions(ctx context.Context, profileID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileByID provides a mock function with given fields: ctx, profileID
+func (_m *ProfileRepository) GetProfileByID(ctx context.Context, profileID uuid.UUID) (*model.Profile, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 *model.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*model.Profile, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *model.Profile); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Profile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileChanges provides a mock function with given fields: ctx, afterSequence, limit
+func (_m *ProfileRepository) GetProfileChanges(ctx context.Context, afterSequence int64, limit int32) ([]*model.ProfileChange, error) {
+	ret := _m.Called(ctx, afterSequence, limit)
+
+	var r0 []*model.ProfileChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int32) ([]*model.ProfileChange, error)); ok {
+		return rf(ctx, afterSequence, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int32) []*model.ProfileChange); ok {
+		r0 = rf(ctx, afterSequence, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ProfileChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int32) error); ok {
+		r1 = rf(ctx, afterSequence, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileChangesHorizon provides a mock function with given fields: ctx
+func (_m *ProfileRepository) GetProfileChangesHorizon(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRefreshTokenByID provides a mock function with given fields: ctx, profileID
 func (_m *ProfileRepository) GetRefreshTokenByID(ctx context.Context, profileID uuid.UUID) ([]byte, error) {
 	ret := _m.Called(ctx, profileID)
@@ -119,6 +493,291 @@ func (_m *ProfileRepository) GetRefreshTokenByID(ctx context.Context, profileID
 	return r0, r1
 }
 
+// GetRoles provides a mock function with given fields: ctx, profileID
+func (_m *ProfileRepository) GetRoles(ctx context.Context, profileID uuid.UUID) ([]string, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]string, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []string); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebAuthnCredentials provides a mock function with given fields: ctx, profileID
+func (_m *ProfileRepository) GetWebAuthnCredentials(ctx context.Context, profileID uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []*model.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*model.WebAuthnCredential, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*model.WebAuthnCredential); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookAttempts provides a mock function with given fields: ctx, subscriptionID, limit
+func (_m *ProfileRepository) GetWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error) {
+	ret := _m.Called(ctx, subscriptionID, limit)
+
+	var r0 []*model.WebhookAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) ([]*model.WebhookAttempt, error)); ok {
+		return rf(ctx, subscriptionID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int32) []*model.WebhookAttempt); ok {
+		r0 = rf(ctx, subscriptionID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int32) error); ok {
+		r1 = rf(ctx, subscriptionID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookSubscriptions provides a mock function with given fields: ctx
+func (_m *ProfileRepository) GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.WebhookSubscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.WebhookSubscription, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.WebhookSubscription); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookSubscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, filter
+func (_m *ProfileRepository) ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*model.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter) ([]*model.AuditEvent, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter) []*model.AuditEvent); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PopWebAuthnSession provides a mock function with given fields: ctx, profileID, ceremony
+func (_m *ProfileRepository) PopWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string) ([]byte, error) {
+	ret := _m.Called(ctx, profileID, ceremony)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) ([]byte, error)); ok {
+		return rf(ctx, profileID, ceremony)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) []byte); ok {
+		r0 = rf(ctx, profileID, ceremony)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, profileID, ceremony)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemLoginToken provides a mock function with given fields: ctx, tokenHash
+func (_m *ProfileRepository) RedeemLoginToken(ctx context.Context, tokenHash []byte) (uuid.UUID, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (uuid.UUID, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) uuid.UUID); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: ctx, profileID, codeHashes
+func (_m *ProfileRepository) ReplaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error {
+	ret := _m.Called(ctx, profileID, codeHashes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, [][]byte) error); ok {
+		r0 = rf(ctx, profileID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRole provides a mock function with given fields: ctx, profileID, role
+func (_m *ProfileRepository) RevokeRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, profileID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEmail provides a mock function with given fields: ctx, profileID, email
+func (_m *ProfileRepository) UpdateEmail(ctx context.Context, profileID uuid.UUID, email string) error {
+	ret := _m.Called(ctx, profileID, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePhone provides a mock function with given fields: ctx, profileID, phone
+func (_m *ProfileRepository) UpdatePhone(ctx context.Context, profileID uuid.UUID, phone string) error {
+	ret := _m.Called(ctx, profileID, phone)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, profileID, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebAuthnSignCount provides a mock function with given fields: ctx, profileID, credentialID, signCount
+func (_m *ProfileRepository) UpdateWebAuthnSignCount(ctx context.Context, profileID uuid.UUID, credentialID []byte, signCount uint32) error {
+	ret := _m.Called(ctx, profileID, credentialID, signCount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte, uint32) error); ok {
+		r0 = rf(ctx, profileID, credentialID, signCount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UsePhoneVerificationAttempt provides a mock function with given fields: ctx, profileID
+func (_m *ProfileRepository) UsePhoneVerificationAttempt(ctx context.Context, profileID uuid.UUID) ([]byte, string, error) {
+	ret := _m.Called(ctx, profileID)
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]byte, string, error)); ok {
+		return rf(ctx, profileID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []byte); ok {
+		r0 = rf(ctx, profileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) string); ok {
+		r1 = rf(ctx, profileID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, profileID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewProfileRepository creates a new instance of ProfileRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProfileRepository(t interface {
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	phoneCodeLength   = 6
+	phoneCodeAlphabet = "0123456789"
+)
+
+// GetPasswordAndIDByPhone calls lower method of ProfileRepository GetPasswordAndIDByPhone
+func (s *ProfileService) GetPasswordAndIDByPhone(ctx context.Context, phone string) (profileID uuid.UUID, password []byte, err error) {
+	profileID, hashedPassword, err := s.r.GetPasswordAndIDByPhone(ctx, strings.TrimSpace(phone))
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("ProfileService -> GetPasswordAndIDByPhone -> %w", err)
+	}
+	return profileID, hashedPassword, nil
+}
+
+// UpdatePhone sets phone to the profile, phone becomes unverified if it was changed
+func (s *ProfileService) UpdatePhone(ctx context.Context, profileID uuid.UUID, phone string) error {
+	err := s.r.UpdatePhone(ctx, profileID, strings.TrimSpace(phone))
+	if err != nil {
+		return fmt.Errorf("ProfileService -> UpdatePhone -> %w", err)
+	}
+	return nil
+}
+
+// IssuePhoneVerificationCode generates short numeric code for current phone of the profile, stores its hash
+// and returns plain code that should be sent to the user
+func (s *ProfileService) IssuePhoneVerificationCode(ctx context.Context, profileID uuid.UUID) (code string, expiresAt time.Time, err error) {
+	code, err = randomString(phoneCodeAlphabet, phoneCodeLength)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssuePhoneVerificationCode -> %w", err)
+	}
+	expiresAt = time.Now().Add(s.cfg.PhoneVerificationTTL)
+	err = s.r.AddPhoneVerificationCode(ctx, profileID, s.hashSecret(code), s.cfg.PhoneVerificationAttempts, expiresAt)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("ProfileService -> IssuePhoneVerificationCode -> %w", err)
+	}
+	return code, expiresAt, nil
+}
+
+// ConfirmPhone spends one attempt of the verification code and marks phone of the profile as verified if code matches
+func (s *ProfileService) ConfirmPhone(ctx context.Context, profileID uuid.UUID, code string) error {
+	codeHash, phone, err := s.r.UsePhoneVerificationAttempt(ctx, profileID)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> ConfirmPhone -> %w", err)
+	}
+	if !hmac.Equal(codeHash, s.hashSecret(strings.TrimSpace(code))) {
+		return fmt.Errorf("ProfileService -> ConfirmPhone -> error: wrong verification code")
+	}
+	err = s.r.ConfirmPhone(ctx, profileID, phone)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> ConfirmPhone -> %w", err)
+	}
+	return nil
+}
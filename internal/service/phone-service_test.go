@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuePhoneVerificationCode(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	var storedHash []byte
+	r.On("AddPhoneVerificationCode", mock.Anything, testProfile.ID, mock.AnythingOfType("[]uint8"), 3, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			storedHash = args.Get(2).([]byte)
+		}).Return(nil)
+
+	testCfg := cfg
+	testCfg.PhoneVerificationTTL = 10 * time.Minute
+	testCfg.PhoneVerificationAttempts = 3
+	s := NewProfileService(r, &testCfg)
+
+	code, expiresAt, err := s.IssuePhoneVerificationCode(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Len(t, code, phoneCodeLength)
+	require.Equal(t, s.hashSecret(code), storedHash)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), expiresAt, time.Minute)
+}
+
+func TestConfirmPhone(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	s := NewProfileService(r, &cfg)
+	r.On("UsePhoneVerificationAttempt", mock.Anything, testProfile.ID).Return(s.hashSecret("123456"), "+375291234567", nil)
+	r.On("ConfirmPhone", mock.Anything, testProfile.ID, "+375291234567").Return(nil)
+
+	err := s.ConfirmPhone(context.Background(), testProfile.ID, "654321")
+	require.Error(t, err)
+	r.AssertNotCalled(t, "ConfirmPhone", mock.Anything, mock.Anything, mock.Anything)
+
+	err = s.ConfirmPhone(context.Background(), testProfile.ID, "123456")
+	require.NoError(t, err)
+	r.AssertNumberOfCalls(t, "UsePhoneVerificationAttempt", 2)
+	r.AssertNumberOfCalls(t, "ConfirmPhone", 1)
+}
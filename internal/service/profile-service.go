@@ -4,6 +4,8 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/distuurbia/profile/internal/config"
 	"github.com/distuurbia/profile/internal/model"
@@ -17,12 +19,45 @@ type ProfileRepository interface {
 	GetRefreshTokenByID(ctx context.Context, profileID uuid.UUID) (hashedRefresh []byte, err error)
 	AddRefreshToken(ctx context.Context, refreshToken []byte, profileID uuid.UUID) error
 	DeleteProfile(ctx context.Context, profileID uuid.UUID) error
+	GetProfileByID(ctx context.Context, profileID uuid.UUID) (*model.Profile, error)
+	ReplaceRecoveryCodes(ctx context.Context, profileID uuid.UUID, codeHashes [][]byte) error
+	ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, codeHash []byte) (codesLeft int32, err error)
+	GetPasswordAndIDByEmail(ctx context.Context, email string) (profileID uuid.UUID, password []byte, err error)
+	UpdateEmail(ctx context.Context, profileID uuid.UUID, email string) error
+	AddEmailVerificationToken(ctx context.Context, profileID uuid.UUID, tokenHash []byte, expiresAt time.Time) error
+	ConfirmEmail(ctx context.Context, tokenHash []byte) (profileID uuid.UUID, err error)
+	GetPasswordAndIDByPhone(ctx context.Context, phone string) (profileID uuid.UUID, password []byte, err error)
+	UpdatePhone(ctx context.Context, profileID uuid.UUID, phone string) error
+	AddPhoneVerificationCode(ctx context.Context, profileID uuid.UUID, codeHash []byte, attempts int, expiresAt time.Time) error
+	UsePhoneVerificationAttempt(ctx context.Context, profileID uuid.UUID) (codeHash []byte, phone string, err error)
+	ConfirmPhone(ctx context.Context, profileID uuid.UUID, phone string) error
+	AddLoginToken(ctx context.Context, profileID uuid.UUID, tokenHash []byte, expiresAt time.Time) error
+	RedeemLoginToken(ctx context.Context, tokenHash []byte) (profileID uuid.UUID, err error)
+	AddWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string, sessionData []byte, expiresAt time.Time) error
+	PopWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string) (sessionData []byte, err error)
+	AddWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredential, recoveryCodeHashes [][]byte) (recoveryCodesStored bool, err error)
+	GetWebAuthnCredentials(ctx context.Context, profileID uuid.UUID) ([]*model.WebAuthnCredential, error)
+	UpdateWebAuthnSignCount(ctx context.Context, profileID uuid.UUID, credentialID []byte, signCount uint32) error
+	DeleteWebAuthnCredential(ctx context.Context, profileID uuid.UUID, credentialID []byte) error
+	AssignRole(ctx context.Context, profileID uuid.UUID, role string) error
+	RevokeRole(ctx context.Context, profileID uuid.UUID, role string) error
+	GetRoles(ctx context.Context, profileID uuid.UUID) ([]string, error)
+	GetPermissions(ctx context.Context, profileID uuid.UUID) ([]string, error)
+	ListAuditEvents(ctx context.Context, filter *model.AuditFilter) ([]*model.AuditEvent, error)
+	CreateWebhookSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	GetWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	GetWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error)
+	GetProfileChanges(ctx context.Context, afterSequence int64, limit int32) ([]*model.ProfileChange, error)
+	GetProfileChangesHorizon(ctx context.Context) (sequence int64, err error)
 }
 
 // ProfileService contains an object of ProfileRepository and config with env variables
 type ProfileService struct {
-	r   ProfileRepository
-	cfg *config.Config
+	r        ProfileRepository
+	cfg      *config.Config
+	feed     ChangeFeed
+	webhooks bool
 }
 
 // NewProfileService creates *ProfileSevice object filles it and returns
@@ -32,6 +67,8 @@ func NewProfileService(r ProfileRepository, cfg *config.Config) *ProfileService
 
 // CreateProfile calls lower method of ProfileRepository CreateProfile
 func (s *ProfileService) CreateProfile(ctx context.Context, profile *model.Profile) (err error) {
+	profile.Email = normalizeEmail(profile.Email)
+	profile.Phone = strings.TrimSpace(profile.Phone)
 	err = s.r.CreateProfile(ctx, profile)
 	if err != nil {
 		return fmt.Errorf("ProfileService -> %w", err)
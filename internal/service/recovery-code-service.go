@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+const (
+	recoveryCodesCount    = 10
+	recoveryCodeLength    = 10
+	recoveryCodeAlphabet  = "abcdefghijklmnopqrstuvwxyz234567"
+	recoveryCodeSeparator = "-"
+)
+
+// GetProfileByID calls lower method of ProfileRepository GetProfileByID
+func (s *ProfileService) GetProfileByID(ctx context.Context, profileID uuid.UUID) (*model.Profile, error) {
+	profile, err := s.r.GetProfileByID(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> GetProfileByID -> %w", err)
+	}
+	return profile, nil
+}
+
+// RegenerateRecoveryCodes generates new set of recovery codes, stores their hashes instead of the previous ones
+// and returns plain codes that must be shown to the user only once
+func (s *ProfileService) RegenerateRecoveryCodes(ctx context.Context, profileID uuid.UUID) (recoveryCodes []string, err error) {
+	recoveryCodes, codeHashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> RegenerateRecoveryCodes -> %w", err)
+	}
+	err = s.r.ReplaceRecoveryCodes(ctx, profileID, codeHashes)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> RegenerateRecoveryCodes -> %w", err)
+	}
+	return recoveryCodes, nil
+}
+
+// newRecoveryCodes generates set of plain recovery codes together with hashes to store
+func (s *ProfileService) newRecoveryCodes() (recoveryCodes []string, codeHashes [][]byte, err error) {
+	recoveryCodes = make([]string, 0, recoveryCodesCount)
+	codeHashes = make([][]byte, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := randomString(recoveryCodeAlphabet, recoveryCodeLength)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ProfileService -> newRecoveryCodes -> %w", err)
+		}
+		code = code[:recoveryCodeLength/2] + recoveryCodeSeparator + code[recoveryCodeLength/2:]
+		recoveryCodes = append(recoveryCodes, code)
+		codeHashes = append(codeHashes, s.hashSecret(normalizeRecoveryCode(code)))
+	}
+	return recoveryCodes, codeHashes, nil
+}
+
+// ConsumeRecoveryCode checks that recovery code belongs to the profile and wasn't used yet, marks it as used and returns number of codes left
+func (s *ProfileService) ConsumeRecoveryCode(ctx context.Context, profileID uuid.UUID, recoveryCode string) (codesLeft int32, err error) {
+	codesLeft, err = s.r.ConsumeRecoveryCode(ctx, profileID, s.hashSecret(normalizeRecoveryCode(recoveryCode)))
+	if err != nil {
+		return 0, fmt.Errorf("ProfileService -> ConsumeRecoveryCode -> %w", err)
+	}
+	return codesLeft, nil
+}
+
+// normalizeRecoveryCode removes separators and spaces from recovery code and lowers its case so user can type it in any form
+func normalizeRecoveryCode(recoveryCode string) string {
+	recoveryCode = strings.ToLower(strings.TrimSpace(recoveryCode))
+	return strings.NewReplacer(recoveryCodeSeparator, "", " ", "").Replace(recoveryCode)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProfileByID(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetProfileByID", mock.Anything, testProfile.ID).Return(&testProfile, nil)
+
+	s := NewProfileService(r, &cfg)
+
+	profile, err := s.GetProfileByID(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, testProfile.Username, profile.Username)
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	var storedHashes [][]byte
+	r.On("ReplaceRecoveryCodes", mock.Anything, testProfile.ID, mock.AnythingOfType("[][]uint8")).
+		Run(func(args mock.Arguments) {
+			storedHashes = args.Get(2).([][]byte)
+		}).Return(nil)
+
+	s := NewProfileService(r, &cfg)
+
+	recoveryCodes, err := s.RegenerateRecoveryCodes(context.Background(), testProfile.ID)
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, recoveryCodesCount)
+	require.Len(t, storedHashes, recoveryCodesCount)
+
+	unique := make(map[string]bool)
+	for i, code := range recoveryCodes {
+		require.Len(t, code, recoveryCodeLength+len(recoveryCodeSeparator))
+		require.NotContains(t, string(storedHashes[i]), normalizeRecoveryCode(code))
+		require.Equal(t, s.hashSecret(normalizeRecoveryCode(code)), storedHashes[i])
+		unique[code] = true
+	}
+	require.Len(t, unique, recoveryCodesCount)
+}
+
+func TestConsumeRecoveryCode(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+
+	s := NewProfileService(r, &cfg)
+	r.On("ConsumeRecoveryCode", mock.Anything, testProfile.ID, s.hashSecret("abcdefghij")).Return(int32(9), nil)
+
+	codesLeft, err := s.ConsumeRecoveryCode(context.Background(), testProfile.ID, " ABCDE-fghij ")
+	require.NoError(t, err)
+	require.Equal(t, int32(9), codesLeft)
+}
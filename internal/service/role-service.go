@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// AllPermissions is a permission that grants every other permission
+const AllPermissions = "*"
+
+type permissionCacheKey struct{}
+
+// permissionCache keeps effective permissions of profiles loaded during one request. Concurrent loads of the same profile
+// share one query that runs without holding the mutex, and a load that overlaps with a change of roles isn't cached
+type permissionCache struct {
+	group       singleflight.Group
+	mu          sync.Mutex
+	permissions map[uuid.UUID][]string
+	generation  uint64
+}
+
+// WithPermissionCache returns copy of context where permissions are loaded from repository once per profile
+func WithPermissionCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, permissionCacheKey{}, &permissionCache{permissions: make(map[uuid.UUID][]string)})
+}
+
+// permissions returns effective permissions of the profile from cache of the request or from repository
+func (s *ProfileService) permissions(ctx context.Context, profileID uuid.UUID) ([]string, error) {
+	cache, ok := ctx.Value(permissionCacheKey{}).(*permissionCache)
+	if !ok {
+		permissions, err := s.r.GetPermissions(ctx, profileID)
+		if err != nil {
+			return nil, fmt.Errorf("permissions -> %w", err)
+		}
+		return permissions, nil
+	}
+
+	cache.mu.Lock()
+	permissions, found := cache.permissions[profileID]
+	generation := cache.generation
+	cache.mu.Unlock()
+	if found {
+		return permissions, nil
+	}
+	loaded, err, _ := cache.group.Do(profileID.String(), func() (interface{}, error) {
+		permissions, err := s.r.GetPermissions(ctx, profileID)
+		if err != nil {
+			return nil, err
+		}
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		if cache.generation == generation {
+			cache.permissions[profileID] = permissions
+		}
+		return permissions, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("permissions -> %w", err)
+	}
+	return loaded.([]string), nil
+}
+
+// forgetPermissions drops cached permissions of the profile after its roles were changed
+func forgetPermissions(ctx context.Context, profileID uuid.UUID) {
+	if cache, ok := ctx.Value(permissionCacheKey{}).(*permissionCache); ok {
+		cache.mu.Lock()
+		delete(cache.permissions, profileID)
+		cache.generation++
+		cache.mu.Unlock()
+		cache.group.Forget(profileID.String())
+	}
+}
+
+// AssignRole calls lower method of ProfileRepository AssignRole
+func (s *ProfileService) AssignRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	err := s.r.AssignRole(ctx, profileID, role)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> AssignRole -> %w", err)
+	}
+	forgetPermissions(ctx, profileID)
+	return nil
+}
+
+// RevokeRole calls lower method of ProfileRepository RevokeRole
+func (s *ProfileService) RevokeRole(ctx context.Context, profileID uuid.UUID, role string) error {
+	err := s.r.RevokeRole(ctx, profileID, role)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> RevokeRole -> %w", err)
+	}
+	forgetPermissions(ctx, profileID)
+	return nil
+}
+
+// GetEffectivePermissions returns roles of the profile and permissions granted by all of them
+func (s *ProfileService) GetEffectivePermissions(ctx context.Context, profileID uuid.UUID) (roles, permissions []string, err error) {
+	roles, err = s.r.GetRoles(ctx, profileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> GetEffectivePermissions -> %w", err)
+	}
+	permissions, err = s.permissions(ctx, profileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> GetEffectivePermissions -> %w", err)
+	}
+	return roles, permissions, nil
+}
+
+// CheckPermission reports whether any role of the profile grants the permission
+func (s *ProfileService) CheckPermission(ctx context.Context, profileID uuid.UUID, permission string) (bool, error) {
+	permissions, err := s.permissions(ctx, profileID)
+	if err != nil {
+		return false, fmt.Errorf("ProfileService -> CheckPermission -> %w", err)
+	}
+	for _, granted := range permissions {
+		if granted == permission || granted == AllPermissions {
+			return true, nil
+		}
+	}
+	return false, nil
+}
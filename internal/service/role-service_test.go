@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPermission(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetPermissions", mock.Anything, testProfile.ID).Return([]string{"profile.read"}, nil)
+
+	s := NewProfileService(r, &cfg)
+
+	ctx := WithPermissionCache(context.Background())
+	allowed, err := s.CheckPermission(ctx, testProfile.ID, "profile.read")
+	require.NoError(t, err)
+	require.True(t, allowed)
+	allowed, err = s.CheckPermission(ctx, testProfile.ID, "profile.delete")
+	require.NoError(t, err)
+	require.False(t, allowed)
+	r.AssertNumberOfCalls(t, "GetPermissions", 1)
+
+	_, err = s.CheckPermission(context.Background(), testProfile.ID, "profile.read")
+	require.NoError(t, err)
+	r.AssertNumberOfCalls(t, "GetPermissions", 2)
+}
+
+func TestCheckPermissionDoesNotBlockOnSlowQuery(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	other := uuid.New()
+	r := new(mocks.ProfileRepository)
+	r.On("GetPermissions", mock.Anything, testProfile.ID).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return([]string{"profile.read"}, nil).Once()
+	r.On("GetPermissions", mock.Anything, other).Return([]string{}, nil).Once()
+
+	s := NewProfileService(r, &cfg)
+
+	ctx := WithPermissionCache(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := s.CheckPermission(ctx, testProfile.ID, "profile.read")
+		done <- err
+	}()
+	<-started
+	allowed, err := s.CheckPermission(ctx, other, "profile.read")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	close(release)
+	require.NoError(t, <-done)
+	allowed, err = s.CheckPermission(ctx, testProfile.ID, "profile.read")
+	require.NoError(t, err)
+	require.True(t, allowed)
+	r.AssertNumberOfCalls(t, "GetPermissions", 2)
+}
+
+func TestCheckPermissionWildcard(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetPermissions", mock.Anything, testProfile.ID).Return([]string{AllPermissions}, nil)
+
+	s := NewProfileService(r, &cfg)
+
+	allowed, err := s.CheckPermission(context.Background(), testProfile.ID, "profile.delete")
+	require.NoError(t, err)
+	require.True(t, allowed)
+}
+
+func TestAssignRoleInvalidatesCache(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetPermissions", mock.Anything, testProfile.ID).Return([]string{}, nil).Once()
+	r.On("GetPermissions", mock.Anything, testProfile.ID).Return([]string{"profile.read"}, nil).Once()
+	r.On("AssignRole", mock.Anything, testProfile.ID, "user").Return(nil)
+	r.On("GetRoles", mock.Anything, testProfile.ID).Return([]string{"user"}, nil)
+
+	s := NewProfileService(r, &cfg)
+
+	ctx := WithPermissionCache(context.Background())
+	allowed, err := s.CheckPermission(ctx, testProfile.ID, "profile.read")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	err = s.AssignRole(ctx, testProfile.ID, "user")
+	require.NoError(t, err)
+	roles, permissions, err := s.GetEffectivePermissions(ctx, testProfile.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user"}, roles)
+	require.Equal(t, []string{"profile.read"}, permissions)
+}
+
+func TestRevokeRole(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("RevokeRole", mock.Anything, testProfile.ID, "admin").Return(nil)
+
+	s := NewProfileService(r, &cfg)
+
+	err := s.RevokeRole(context.Background(), testProfile.ID, "admin")
+	require.NoError(t, err)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const tokenSize = 32
+
+// Labels of keys the service derives from SecretKey, every purpose gets its own key. HS256 bearer tokens are still
+// checked against SecretKey itself because issuers sign them with it
+const (
+	pepperKeyLabel = "profile/secret-pepper"
+	// RateLimitKeyLabel labels the key of HMAC that hides callers in keys of rate limit buckets
+	RateLimitKeyLabel = "profile/rate-limit"
+)
+
+// DeriveKey returns 256-bit key for the purpose named by label, derived from secretKey with HKDF-SHA256
+func DeriveKey(secretKey, label string) []byte {
+	key := make([]byte, sha256.Size)
+	// HKDF-SHA256 can't fail to expand 32 bytes
+	_, _ = io.ReadFull(hkdf.New(sha256.New, []byte(secretKey), nil, []byte(label)), key)
+	return key
+}
+
+// hashSecret returns HMAC-SHA256 of the secret keyed with pepper derived from SecretKey, so one-time secrets are never stored in plain text
+func (s *ProfileService) hashSecret(secret string) []byte {
+	mac := hmac.New(sha256.New, DeriveKey(s.cfg.SecretKey, pepperKeyLabel))
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
+
+// randomString returns string of the given length with symbols randomly picked from alphabet
+func randomString(alphabet string, length int) (string, error) {
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", fmt.Errorf("randomString -> %w", err)
+		}
+		buf[i] = alphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+// randomToken returns url-safe token with 256 bits of entropy
+func randomToken() (string, error) {
+	buf := make([]byte, tokenSize)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("randomToken -> %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
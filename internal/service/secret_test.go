@@ -0,0 +1,22 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveKey(t *testing.T) {
+	rateLimitKey := DeriveKey("secret", RateLimitKeyLabel)
+	require.Len(t, rateLimitKey, sha256.Size)
+	require.Equal(t, rateLimitKey, DeriveKey("secret", RateLimitKeyLabel))
+	require.NotEqual(t, rateLimitKey, DeriveKey("secret", pepperKeyLabel))
+	require.NotEqual(t, rateLimitKey, DeriveKey("other secret", RateLimitKeyLabel))
+
+	s := NewProfileService(nil, &cfg)
+	mac := hmac.New(sha256.New, []byte(cfg.SecretKey))
+	mac.Write([]byte("token"))
+	require.NotEqual(t, mac.Sum(nil), s.hashSecret("token"))
+}
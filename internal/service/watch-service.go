@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/watch"
+	"github.com/google/uuid"
+)
+
+const changesPageSize = 500
+
+// ErrChangesExpired means that changes after the requested sequence aren't kept anymore and the watcher has to reload profiles
+var ErrChangesExpired = errors.New("changes after the sequence aren't kept anymore")
+
+// ChangeFeed is an interface of watch.Hub that fans out live changes of profiles
+type ChangeFeed interface {
+	Subscribe() (*watch.Subscription, error)
+}
+
+// SetChangeFeed sets live feed of profile changes used by WatchProfiles
+func (s *ProfileService) SetChangeFeed(feed ChangeFeed) {
+	s.feed = feed
+}
+
+// WatchProfiles passes changes of profiles with the given ids, or of all profiles if ids are empty, to send until context is done.
+// Changes after fromSequence are read from the database first, zero fromSequence watches changes from now on.
+// Subscriptions dropped by the feed are resumed after the last seen sequence, so no change is skipped
+func (s *ProfileService) WatchProfiles(ctx context.Context, fromSequence int64, ids []uuid.UUID,
+	send func(*model.ProfileChange) error) error {
+	if s.feed == nil {
+		return fmt.Errorf("ProfileService -> WatchProfiles -> error: change feed isn't configured")
+	}
+	watched := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		watched[id] = true
+	}
+	filtered := func(change *model.ProfileChange) error {
+		if len(watched) > 0 && !watched[change.ProfileID] {
+			return nil
+		}
+		return send(change)
+	}
+
+	last, positioned := fromSequence, fromSequence > 0
+	for {
+		sub, err := s.feed.Subscribe()
+		if err != nil {
+			return fmt.Errorf("ProfileService -> WatchProfiles -> %w", err)
+		}
+		if !positioned {
+			last, positioned = sub.Since(), true
+		}
+		last, err = s.watchChanges(ctx, sub, last, filtered)
+		sub.Close()
+		if !errors.Is(err, watch.ErrLagged) {
+			if err != nil {
+				return fmt.Errorf("ProfileService -> WatchProfiles -> %w", err)
+			}
+			return nil
+		}
+	}
+}
+
+// watchChanges replays kept changes after last and then passes live changes of the subscription until it's dropped,
+// it returns sequence of the last passed change
+func (s *ProfileService) watchChanges(ctx context.Context, sub *watch.Subscription, last int64,
+	send func(*model.ProfileChange) error) (int64, error) {
+	last, err := s.replayChanges(ctx, last, sub.Since(), send)
+	if err != nil {
+		return last, err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case change, ok := <-sub.C():
+			if !ok {
+				return last, sub.Err()
+			}
+			if change.Sequence <= last {
+				continue
+			}
+			last = change.Sequence
+			if err = send(change); err != nil {
+				return last, err
+			}
+		}
+	}
+}
+
+// replayChanges passes changes kept in the database after last until the sequence the subscription started at.
+// Sequences have gaps left by rolled back transactions, so only the horizon of deleted changes tells whether a change
+// after last is gone. It's read after every page because changes may be deleted right before the page is read
+func (s *ProfileService) replayChanges(ctx context.Context, last, since int64, send func(*model.ProfileChange) error) (int64, error) {
+	for last < since {
+		changes, err := s.r.GetProfileChanges(ctx, last, changesPageSize)
+		if err != nil {
+			return last, err
+		}
+		horizon, err := s.r.GetProfileChangesHorizon(ctx)
+		if err != nil {
+			return last, err
+		}
+		if horizon > last {
+			return last, ErrChangesExpired
+		}
+		if len(changes) == 0 {
+			return last, nil
+		}
+		for _, change := range changes {
+			last = change.Sequence
+			if err = send(change); err != nil {
+				return last, err
+			}
+		}
+	}
+	return last, nil
+}
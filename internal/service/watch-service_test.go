@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/distuurbia/profile/internal/watch"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// feedSource is watch.Source that reports latest sequence and then passes changes pushed by the test
+type feedSource struct {
+	latest  int64
+	changes chan *model.ProfileChange
+}
+
+func (s *feedSource) Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error {
+	ready(s.latest)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case change := <-s.changes:
+			notify(change)
+		}
+	}
+}
+
+func runFeed(t *testing.T, latest int64) (*watch.Hub, *feedSource) {
+	source := &feedSource{latest: latest, changes: make(chan *model.ProfileChange)}
+	hub := watch.NewHub(source, 16, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+	t.Cleanup(cancel)
+	require.Eventually(t, func() bool {
+		sub, err := hub.Subscribe()
+		if err != nil {
+			return false
+		}
+		sub.Close()
+		return true
+	}, time.Second, time.Millisecond)
+	return hub, source
+}
+
+func profileChange(seq int64, id uuid.UUID) *model.ProfileChange {
+	return &model.ProfileChange{Sequence: seq, ProfileID: id, Operation: "UPDATE", ChangedAt: time.Now()}
+}
+
+func TestWatchProfilesReplaysAndFilters(t *testing.T) {
+	watched, other := uuid.New(), uuid.New()
+	r := new(mocks.ProfileRepository)
+	// sequence 3 was never committed, the gap isn't mistaken for deleted changes
+	r.On("GetProfileChangesHorizon", mock.Anything).Return(int64(2), nil)
+	r.On("GetProfileChanges", mock.Anything, int64(2), int32(changesPageSize)).
+		Return([]*model.ProfileChange{profileChange(4, watched), profileChange(5, watched)}, nil)
+
+	hub, source := runFeed(t, 5)
+	s := NewProfileService(r, &cfg)
+	s.SetChangeFeed(hub)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var received []int64
+	err := s.WatchProfiles(ctx, 2, []uuid.UUID{watched}, func(change *model.ProfileChange) error {
+		received = append(received, change.Sequence)
+		switch change.Sequence {
+		case 5:
+			go func() {
+				source.changes <- profileChange(6, other)
+				source.changes <- profileChange(7, watched)
+			}()
+		case 7:
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{4, 5, 7}, received)
+}
+
+func TestWatchProfilesExpired(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetProfileChanges", mock.Anything, int64(2), int32(changesPageSize)).
+		Return([]*model.ProfileChange{profileChange(4, uuid.New()), profileChange(5, uuid.New())}, nil)
+	r.On("GetProfileChangesHorizon", mock.Anything).Return(int64(3), nil)
+
+	hub, _ := runFeed(t, 5)
+	s := NewProfileService(r, &cfg)
+	s.SetChangeFeed(hub)
+
+	var received []int64
+	err := s.WatchProfiles(context.Background(), 2, nil, func(change *model.ProfileChange) error {
+		received = append(received, change.Sequence)
+		return nil
+	})
+	require.ErrorIs(t, err, ErrChangesExpired)
+	require.Empty(t, received)
+}
+
+func TestWatchProfilesExpiredWhileReplaying(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("GetProfileChanges", mock.Anything, int64(2), int32(changesPageSize)).
+		Return([]*model.ProfileChange{profileChange(3, uuid.New())}, nil)
+	r.On("GetProfileChangesHorizon", mock.Anything).Return(int64(0), nil).Once()
+	// changes 4 and 5 are deleted before the second page is read
+	r.On("GetProfileChanges", mock.Anything, int64(3), int32(changesPageSize)).
+		Return([]*model.ProfileChange{profileChange(6, uuid.New())}, nil)
+	r.On("GetProfileChangesHorizon", mock.Anything).Return(int64(5), nil).Once()
+
+	hub, _ := runFeed(t, 6)
+	s := NewProfileService(r, &cfg)
+	s.SetChangeFeed(hub)
+
+	var received []int64
+	err := s.WatchProfiles(context.Background(), 2, nil, func(change *model.ProfileChange) error {
+		received = append(received, change.Sequence)
+		return nil
+	})
+	require.ErrorIs(t, err, ErrChangesExpired)
+	require.Equal(t, []int64{3}, received)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/primary"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	webAuthnRegistration = "registration"
+	webAuthnLogin        = "login"
+)
+
+// webAuthnUser adapts profile with its credentials to webauthn.User
+type webAuthnUser struct {
+	profile     *model.Profile
+	credentials []*model.WebAuthnCredential
+}
+
+// WebAuthnID returns id of the profile as user handle
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.profile.ID[:]
+}
+
+// WebAuthnName returns username of the profile
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.profile.Username
+}
+
+// WebAuthnDisplayName returns username of the profile
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.profile.Username
+}
+
+// WebAuthnIcon returns nothing because icons are deprecated by specification
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+// WebAuthnCredentials converts stored credentials of the profile to webauthn.Credential
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, credential := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.ID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+// webAuthn creates relying party from config
+func (s *ProfileService) webAuthn() (*webauthn.WebAuthn, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    s.cfg.WebAuthnTimeout,
+		TimeoutUVD: s.cfg.WebAuthnTimeout,
+	}
+	return webauthn.New(&webauthn.Config{
+		RPID:          s.cfg.WebAuthnRPID,
+		RPDisplayName: s.cfg.WebAuthnRPDisplayName,
+		RPOrigins:     s.cfg.WebAuthnRPOrigins,
+		Timeouts:      webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+}
+
+// webAuthnUser reads profile with its credentials from the primary, ceremonies follow writes of the profile
+// and its credentials that replicas may not have yet
+func (s *ProfileService) webAuthnUser(ctx context.Context, profileID uuid.UUID) (*webAuthnUser, error) {
+	ctx = primary.Pin(ctx)
+	profile, err := s.r.GetProfileByID(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("webAuthnUser -> %w", err)
+	}
+	credentials, err := s.r.GetWebAuthnCredentials(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("webAuthnUser -> %w", err)
+	}
+	return &webAuthnUser{profile: profile, credentials: credentials}, nil
+}
+
+// saveWebAuthnSession stores state of the ceremony until it's finished
+func (s *ProfileService) saveWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string, session *webauthn.SessionData) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("saveWebAuthnSession -> %w", err)
+	}
+	err = s.r.AddWebAuthnSession(ctx, profileID, ceremony, sessionData, session.Expires)
+	if err != nil {
+		return fmt.Errorf("saveWebAuthnSession -> %w", err)
+	}
+	return nil
+}
+
+// popWebAuthnSession takes state of the ceremony so it can't be finished twice
+func (s *ProfileService) popWebAuthnSession(ctx context.Context, profileID uuid.UUID, ceremony string) (*webauthn.SessionData, error) {
+	sessionData, err := s.r.PopWebAuthnSession(ctx, profileID, ceremony)
+	if err != nil {
+		return nil, fmt.Errorf("popWebAuthnSession -> %w", err)
+	}
+	var session webauthn.SessionData
+	err = json.Unmarshal(sessionData, &session)
+	if err != nil {
+		return nil, fmt.Errorf("popWebAuthnSession -> %w", err)
+	}
+	return &session, nil
+}
+
+// BeginWebAuthnRegistration starts registration ceremony of new passkey and returns options for navigator.credentials.create in JSON
+func (s *ProfileService) BeginWebAuthnRegistration(ctx context.Context, profileID uuid.UUID) (options []byte, err error) {
+	wa, err := s.webAuthn()
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnRegistration -> %w", err)
+	}
+	user, err := s.webAuthnUser(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnRegistration -> %w", err)
+	}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, credential := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := wa.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnRegistration -> %w", err)
+	}
+	err = s.saveWebAuthnSession(ctx, profileID, webAuthnRegistration, session)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnRegistration -> %w", err)
+	}
+	options, err = json.Marshal(creation)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnRegistration -> %w", err)
+	}
+	return options, nil
+}
+
+// FinishWebAuthnRegistration verifies attestation response of the authenticator and stores new passkey of the profile.
+// The first passkey enables 2FA, so recovery codes are generated for the profile unless it already has unused ones
+func (s *ProfileService) FinishWebAuthnRegistration(ctx context.Context, profileID uuid.UUID, response []byte) (
+	credential *model.WebAuthnCredential, recoveryCodes []string, err error) {
+	wa, err := s.webAuthn()
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+	session, err := s.popWebAuthnSession(ctx, profileID, webAuthnRegistration)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+	user, err := s.webAuthnUser(ctx, profileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+	created, err := wa.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+
+	credential = &model.WebAuthnCredential{
+		ID:              created.ID,
+		ProfileID:       profileID,
+		PublicKey:       created.PublicKey,
+		SignCount:       created.Authenticator.SignCount,
+		Transports:      make([]string, 0, len(created.Transport)),
+		AAGUID:          created.Authenticator.AAGUID,
+		AttestationType: created.AttestationType,
+	}
+	for _, transport := range created.Transport {
+		credential.Transports = append(credential.Transports, string(transport))
+	}
+	// codes are stored only with the first passkey of the profile that has none left, the repository checks it
+	// in the same transaction as the insert, so concurrent registrations can't return codes that were replaced
+	var codeHashes [][]byte
+	if len(user.credentials) == 0 && user.profile.RecoveryCodesLeft == 0 {
+		recoveryCodes, codeHashes, err = s.newRecoveryCodes()
+		if err != nil {
+			return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+		}
+	}
+	stored, err := s.r.AddWebAuthnCredential(ctx, credential, codeHashes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ProfileService -> FinishWebAuthnRegistration -> %w", err)
+	}
+	if !stored {
+		return credential, nil, nil
+	}
+	return credential, recoveryCodes, nil
+}
+
+// BeginWebAuthnLogin starts assertion ceremony with passkeys of the profile and returns options for navigator.credentials.get in JSON
+func (s *ProfileService) BeginWebAuthnLogin(ctx context.Context, profileID uuid.UUID) (options []byte, err error) {
+	wa, err := s.webAuthn()
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnLogin -> %w", err)
+	}
+	user, err := s.webAuthnUser(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnLogin -> %w", err)
+	}
+	assertion, session, err := wa.BeginLogin(user)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnLogin -> %w", err)
+	}
+	err = s.saveWebAuthnSession(ctx, profileID, webAuthnLogin, session)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnLogin -> %w", err)
+	}
+	options, err = json.Marshal(assertion)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> BeginWebAuthnLogin -> %w", err)
+	}
+	return options, nil
+}
+
+// FinishWebAuthnLogin verifies assertion response of the authenticator and rejects it if sign count of the passkey went back
+func (s *ProfileService) FinishWebAuthnLogin(ctx context.Context, profileID uuid.UUID, response []byte) error {
+	wa, err := s.webAuthn()
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	session, err := s.popWebAuthnSession(ctx, profileID, webAuthnLogin)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	user, err := s.webAuthnUser(ctx, profileID)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	credential, err := wa.ValidateLogin(user, *session, parsed)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	if credential.Authenticator.CloneWarning {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> error: sign count of the credential went back, authenticator may be cloned")
+	}
+	err = s.r.UpdateWebAuthnSignCount(ctx, profileID, credential.ID, credential.Authenticator.SignCount)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> FinishWebAuthnLogin -> %w", err)
+	}
+	return nil
+}
+
+// ListWebAuthnCredentials calls lower method of ProfileRepository GetWebAuthnCredentials
+func (s *ProfileService) ListWebAuthnCredentials(ctx context.Context, profileID uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	credentials, err := s.r.GetWebAuthnCredentials(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> ListWebAuthnCredentials -> %w", err)
+	}
+	return credentials, nil
+}
+
+// DeleteWebAuthnCredential calls lower method of ProfileRepository DeleteWebAuthnCredential
+func (s *ProfileService) DeleteWebAuthnCredential(ctx context.Context, profileID uuid.UUID, credentialID []byte) error {
+	err := s.r.DeleteWebAuthnCredential(ctx, profileID, credentialID)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> DeleteWebAuthnCredential -> %w", err)
+	}
+	return nil
+}
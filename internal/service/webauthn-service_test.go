@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/primary"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost"
+
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// softAuthenticator is a software passkey that answers WebAuthn ceremonies like a browser with platform authenticator
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	aaguid       []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+	return &softAuthenticator{key: key, credentialID: credentialID, aaguid: make([]byte, 16)}
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) []byte {
+	clientData, err := json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    testOrigin,
+	})
+	require.NoError(t, err)
+	return clientData
+}
+
+// create answers navigator.credentials.create with "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options []byte) []byte {
+	var creation protocol.CredentialCreation
+	require.NoError(t, json.Unmarshal(options, &creation))
+
+	publicKey, err := cbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	authData := a.authData(flagUserPresent | flagUserVerified | flagAttestedData)
+	authData = append(authData, a.aaguid...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, protocol.CreateCeremony, creation.Response.Challenge)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// get answers navigator.credentials.get signing with the private key of the passkey
+func (a *softAuthenticator) get(t *testing.T, options []byte) []byte {
+	var assertion protocol.CredentialAssertion
+	require.NoError(t, json.Unmarshal(options, &assertion))
+
+	authData := a.authData(flagUserPresent | flagUserVerified)
+	clientData := a.clientData(t, protocol.AssertCeremony, assertion.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// webAuthnRepository makes mock repository keep sessions and credentials between calls
+func webAuthnRepository(profileID uuid.UUID) *mocks.ProfileRepository {
+	r := new(mocks.ProfileRepository)
+	sessions := make(map[string][]byte)
+	var credentials []*model.WebAuthnCredential
+
+	r.On("GetProfileByID", mock.MatchedBy(primary.Pinned), profileID).Return(&model.Profile{ID: profileID, Username: testProfile.Username}, nil)
+	r.On("GetWebAuthnCredentials", mock.MatchedBy(primary.Pinned), profileID).Return(
+		func(context.Context, uuid.UUID) []*model.WebAuthnCredential { return credentials },
+		func(context.Context, uuid.UUID) error { return nil })
+	r.On("AddWebAuthnSession", mock.Anything, profileID, mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"),
+		mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		sessions[args.String(2)] = args.Get(3).([]byte)
+	}).Return(nil)
+	r.On("PopWebAuthnSession", mock.Anything, profileID, mock.AnythingOfType("string")).Return(
+		func(_ context.Context, _ uuid.UUID, ceremony string) []byte {
+			sessionData := sessions[ceremony]
+			delete(sessions, ceremony)
+			return sessionData
+		},
+		func(context.Context, uuid.UUID, string) error { return nil })
+	r.On("AddWebAuthnCredential", mock.Anything, mock.AnythingOfType("*model.WebAuthnCredential"), mock.AnythingOfType("[][]uint8")).Return(
+		func(_ context.Context, credential *model.WebAuthnCredential, codeHashes [][]byte) bool {
+			stored := len(codeHashes) > 0 && len(credentials) == 0
+			credentials = append(credentials, credential)
+			return stored
+		},
+		func(context.Context, *model.WebAuthnCredential, [][]byte) error { return nil })
+	r.On("UpdateWebAuthnSignCount", mock.Anything, profileID, mock.AnythingOfType("[]uint8"), mock.AnythingOfType("uint32")).
+		Run(func(args mock.Arguments) {
+			credentials[0].SignCount = args.Get(3).(uint32)
+		}).Return(nil)
+	return r
+}
+
+func TestWebAuthnCeremonies(t *testing.T) {
+	profileID := uuid.New()
+	r := webAuthnRepository(profileID)
+
+	testCfg := cfg
+	testCfg.WebAuthnRPID = testRPID
+	testCfg.WebAuthnRPDisplayName = "Profile"
+	testCfg.WebAuthnRPOrigins = []string{testOrigin}
+	testCfg.WebAuthnTimeout = time.Minute
+	s := NewProfileService(r, &testCfg)
+
+	authenticator := newSoftAuthenticator(t)
+
+	options, err := s.BeginWebAuthnRegistration(context.Background(), profileID)
+	require.NoError(t, err)
+	credential, recoveryCodes, err := s.FinishWebAuthnRegistration(context.Background(), profileID, authenticator.create(t, options))
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, recoveryCodesCount)
+	require.Equal(t, authenticator.credentialID, credential.ID)
+	require.Equal(t, []string{"internal"}, credential.Transports)
+	require.Equal(t, authenticator.aaguid, credential.AAGUID)
+	require.Equal(t, "none", credential.AttestationType)
+
+	authenticator.signCount = 1
+	options, err = s.BeginWebAuthnLogin(context.Background(), profileID)
+	require.NoError(t, err)
+	err = s.FinishWebAuthnLogin(context.Background(), profileID, authenticator.get(t, options))
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), credential.SignCount)
+
+	authenticator.signCount = 0
+	options, err = s.BeginWebAuthnLogin(context.Background(), profileID)
+	require.NoError(t, err)
+	err = s.FinishWebAuthnLogin(context.Background(), profileID, authenticator.get(t, options))
+	require.ErrorContains(t, err, "sign count")
+	r.AssertNumberOfCalls(t, "UpdateWebAuthnSignCount", 1)
+
+	authenticator.signCount = 2
+	err = s.FinishWebAuthnLogin(context.Background(), profileID, authenticator.get(t, options))
+	require.Error(t, err)
+
+	second := newSoftAuthenticator(t)
+	options, err = s.BeginWebAuthnRegistration(context.Background(), profileID)
+	require.NoError(t, err)
+	_, recoveryCodes, err = s.FinishWebAuthnRegistration(context.Background(), profileID, second.create(t, options))
+	require.NoError(t, err)
+	require.Nil(t, recoveryCodes)
+	r.AssertCalled(t, "AddWebAuthnCredential", mock.Anything, mock.Anything, [][]byte(nil))
+	r.AssertNotCalled(t, "ReplaceRecoveryCodes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteWebAuthnCredential(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("DeleteWebAuthnCredential", mock.Anything, testProfile.ID, []byte("credential")).Return(nil)
+
+	s := NewProfileService(r, &cfg)
+
+	err := s.DeleteWebAuthnCredential(context.Background(), testProfile.ID, []byte("credential"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+)
+
+// DefaultWebhookAttemptsLimit is the number of delivery attempts returned when the request doesn't set it
+const DefaultWebhookAttemptsLimit = 100
+
+// ErrWebhooksDisabled means that nothing delivers webhooks with the configured storage backend
+var ErrWebhooksDisabled = errors.New("webhooks aren't delivered with this storage backend")
+
+// EnableWebhooks marks that webhook deliveries are dispatched, webhook methods return ErrWebhooksDisabled until it's called
+// so subscriptions aren't accepted by backends that never deliver them
+func (s *ProfileService) EnableWebhooks() {
+	s.webhooks = true
+}
+
+// CreateWebhookSubscription generates signing secret of the subscription, saves it and returns the subscription with its secret
+func (s *ProfileService) CreateWebhookSubscription(ctx context.Context, endpoint string, eventTypes []string) (
+	*model.WebhookSubscription, error) {
+	if !s.webhooks {
+		return nil, fmt.Errorf("ProfileService -> CreateWebhookSubscription -> %w", ErrWebhooksDisabled)
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> CreateWebhookSubscription -> %w", err)
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return nil, fmt.Errorf("ProfileService -> CreateWebhookSubscription -> error: url must use http or https scheme")
+	}
+	secret, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> CreateWebhookSubscription -> %w", err)
+	}
+	subscription := &model.WebhookSubscription{ID: uuid.New(), URL: endpoint, EventTypes: eventTypes, Secret: secret}
+	err = s.r.CreateWebhookSubscription(ctx, subscription)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> CreateWebhookSubscription -> %w", err)
+	}
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions calls lower method of ProfileRepository GetWebhookSubscriptions
+func (s *ProfileService) ListWebhookSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	if !s.webhooks {
+		return nil, fmt.Errorf("ProfileService -> ListWebhookSubscriptions -> %w", ErrWebhooksDisabled)
+	}
+	subscriptions, err := s.r.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> ListWebhookSubscriptions -> %w", err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription calls lower method of ProfileRepository DeleteWebhookSubscription
+func (s *ProfileService) DeleteWebhookSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	if !s.webhooks {
+		return fmt.Errorf("ProfileService -> DeleteWebhookSubscription -> %w", ErrWebhooksDisabled)
+	}
+	err := s.r.DeleteWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("ProfileService -> DeleteWebhookSubscription -> %w", err)
+	}
+	return nil
+}
+
+// ListWebhookAttempts calls lower method of ProfileRepository GetWebhookAttempts with default limit if it's not set
+func (s *ProfileService) ListWebhookAttempts(ctx context.Context, subscriptionID uuid.UUID, limit int32) ([]*model.WebhookAttempt, error) {
+	if !s.webhooks {
+		return nil, fmt.Errorf("ProfileService -> ListWebhookAttempts -> %w", ErrWebhooksDisabled)
+	}
+	if limit == 0 {
+		limit = DefaultWebhookAttemptsLimit
+	}
+	attempts, err := s.r.GetWebhookAttempts(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ProfileService -> ListWebhookAttempts -> %w", err)
+	}
+	return attempts, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/service/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookSubscription(t *testing.T) {
+	r := new(mocks.ProfileRepository)
+	r.On("CreateWebhookSubscription", mock.Anything, mock.AnythingOfType("*model.WebhookSubscription")).Return(nil)
+
+	s := NewProfileService(r, &cfg)
+	_, err := s.CreateWebhookSubscription(context.Background(), "https://partner.example.com/hooks", nil)
+	require.ErrorIs(t, err, ErrWebhooksDisabled)
+	s.EnableWebhooks()
+
+	eventTypes := []string{model.EventProfileDeleted}
+	subscription, err := s.CreateWebhookSubscription(context.Background(), "https://partner.example.com/hooks", eventTypes)
+	require.NoError(t, err)
+	require.NotEmpty(t, subscription.Secret)
+	require.Equal(t, eventTypes, subscription.EventTypes)
+
+	_, err = s.CreateWebhookSubscription(context.Background(), "ftp://partner.example.com/hooks", eventTypes)
+	require.Error(t, err)
+	r.AssertNumberOfCalls(t, "CreateWebhookSubscription", 1)
+}
@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// chainedQueryTracer calls several tracers because pgx.ConnConfig accepts only one
+type chainedQueryTracer []pgx.QueryTracer
+
+// ChainQueryTracers combines tracers into one, each of them receives context returned by the previous one
+func ChainQueryTracers(tracers ...pgx.QueryTracer) pgx.QueryTracer {
+	return chainedQueryTracer(tracers)
+}
+
+// TraceQueryStart calls tracers in order
+func (c chainedQueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	for _, tracer := range c {
+		ctx = tracer.TraceQueryStart(ctx, conn, data)
+	}
+	return ctx
+}
+
+// TraceQueryEnd calls tracers in reverse order
+func (c chainedQueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	for i := len(c) - 1; i >= 0; i-- {
+		c[i].TraceQueryEnd(ctx, conn, data)
+	}
+}
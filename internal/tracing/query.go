@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type querySpanKey struct{}
+
+// queryTracer starts span of every query that is sent inside of traced call
+type queryTracer struct {
+	tracer trace.Tracer
+}
+
+// QueryTracer returns pgx.QueryTracer that makes child span for each repository query. Arguments of queries
+// aren't recorded because they contain passwords and hashes of secrets
+func QueryTracer(tp trace.TracerProvider) pgx.QueryTracer {
+	return &queryTracer{tracer: tp.Tracer(tracerName)}
+}
+
+// TraceQueryStart starts span of the query if context already belongs to a trace, so background queries don't start new traces
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	statement := strings.Join(strings.Fields(data.SQL), " ")
+	operation, _, _ := strings.Cut(statement, " ")
+	operation = strings.ToUpper(operation)
+	ctx, span := t.tracer.Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(statement), semconv.DBOperation(operation)))
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+// TraceQueryEnd records error of the query and ends its span
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(querySpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.End()
+}
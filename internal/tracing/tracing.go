@@ -0,0 +1,76 @@
+// Package tracing configures OpenTelemetry spans of gRPC calls and repository queries
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/distuurbia/profile/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Exporters that can be set to TracingExporter of config
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const (
+	serviceName = "profile"
+	tracerName  = "github.com/distuurbia/profile"
+)
+
+// NewTracerProvider creates tracer provider that samples and exports spans as TracingExporter of config says
+func NewTracerProvider(ctx context.Context, cfg *config.Config) (*sdktrace.TracerProvider, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	}
+	switch cfg.TracingExporter {
+	case ExporterNone:
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("NewTracerProvider -> %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.TracingOTLPEndpoint)}
+		if cfg.TracingOTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("NewTracerProvider -> %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("NewTracerProvider -> error: unknown tracing exporter %q", cfg.TracingExporter)
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+// Propagator returns W3C trace context and baggage propagator used to continue traces of clients
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// UnaryServerInterceptor starts span of every unary call as a child of the trace propagated by client
+func UnaryServerInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPropagators(Propagator()))
+}
+
+// StreamServerInterceptor starts span of every stream as a child of the trace propagated by client
+func StreamServerInterceptor(tp trace.TracerProvider) grpc.StreamServerInterceptor {
+	return otelgrpc.StreamServerInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPropagators(Propagator()))
+}
@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/distuurbia/profile/internal/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID    = "00f067aa0ba902b7"
+	testTraceParent = "00-" + testTraceID + "-" + testParentID + "-01"
+)
+
+func newTestProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func TestUnaryServerInterceptorContinuesTrace(t *testing.T) {
+	tp, exporter := newTestProvider()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(UnaryServerInterceptor(tp)))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", testTraceParent)
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "grpc.health.v1.Health/Check", spans[0].Name)
+	require.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	require.Equal(t, testTraceID, spans[0].SpanContext.TraceID().String())
+	require.Equal(t, testParentID, spans[0].Parent.SpanID().String())
+}
+
+func TestQueryTracer(t *testing.T) {
+	tp, exporter := newTestProvider()
+	tracer := QueryTracer(tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "ProfileService/Login")
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: `select id, password
+		FROM profiles WHERE username = $1`, Args: []interface{}{"secret-username"}})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	require.Equal(t, "postgres SELECT", query.Name)
+	require.Equal(t, trace.SpanKindClient, query.SpanKind)
+	require.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	require.Contains(t, query.Attributes, semconv.DBStatement("select id, password FROM profiles WHERE username = $1"))
+	require.Contains(t, query.Attributes, semconv.DBSystemPostgreSQL)
+	for _, attr := range query.Attributes {
+		require.NotContains(t, attr.Value.Emit(), "secret-username")
+	}
+	require.Equal(t, codes.Error, query.Status.Code)
+}
+
+func TestQueryTracerSkipsQueriesOutsideOfTrace(t *testing.T) {
+	tp, exporter := newTestProvider()
+	tracer := QueryTracer(tp)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	require.Empty(t, exporter.GetSpans())
+}
+
+type recordingTracer struct {
+	name  string
+	calls *[]string
+}
+
+func (r recordingTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	*r.calls = append(*r.calls, "start "+r.name)
+	return ctx
+}
+
+func (r recordingTracer) TraceQueryEnd(_ context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	*r.calls = append(*r.calls, "end "+r.name)
+}
+
+func TestChainQueryTracers(t *testing.T) {
+	var calls []string
+	tracer := ChainQueryTracers(recordingTracer{name: "a", calls: &calls}, recordingTracer{name: "b", calls: &calls})
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	require.Equal(t, []string{"start a", "start b", "end b", "end a"}, calls)
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	for _, exporter := range []string{ExporterNone, ExporterStdout, ExporterOTLP} {
+		tp, err := NewTracerProvider(context.Background(), &config.Config{TracingExporter: exporter,
+			TracingOTLPEndpoint: "localhost:4317", TracingOTLPInsecure: true, TracingSampleRatio: 1})
+		require.NoError(t, err, exporter)
+		require.NoError(t, tp.Shutdown(context.Background()))
+	}
+
+	_, err := NewTracerProvider(context.Background(), &config.Config{TracingExporter: "jaeger"})
+	require.Error(t, err)
+}
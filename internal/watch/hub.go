@@ -0,0 +1,168 @@
+// Package watch fans out profile changes received by a single database listener to subscribers
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrLagged means that subscription was dropped because subscriber fell behind or listener reconnected,
+	// changes after the last received sequence should be read from the database
+	ErrLagged = errors.New("subscription lagged behind profile changes")
+	// ErrNotListening means that listener isn't connected to the database yet
+	ErrNotListening = errors.New("profile changes listener isn't connected")
+	// ErrClosed means that hub is shut down
+	ErrClosed = errors.New("profile changes hub is closed")
+)
+
+// Source is a database connection that notifies about profile changes, it's implemented by repository.ChangeRepository
+type Source interface {
+	Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error
+}
+
+// Subscription receives live changes from Hub
+type Subscription struct {
+	hub    *Hub
+	ch     chan *model.ProfileChange
+	since  int64
+	err    error
+	closed bool
+}
+
+// C returns channel of changes, it's closed when the subscription is dropped
+func (s *Subscription) C() <-chan *model.ProfileChange {
+	return s.ch
+}
+
+// Since returns sequence of the last change before the subscription, C receives only changes after it
+func (s *Subscription) Since() int64 {
+	return s.since
+}
+
+// Err returns reason why C was closed, it's nil while the subscription is active
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close unsubscribes from the hub
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, ErrClosed)
+}
+
+// Hub keeps single Source listening and broadcasts its changes to subscriptions
+type Hub struct {
+	source  Source
+	buffer  int
+	backoff time.Duration
+
+	mu        sync.Mutex
+	listening bool
+	closed    bool
+	latest    int64
+	subs      map[*Subscription]struct{}
+}
+
+// NewHub creates Hub, buffer is the number of changes that subscriber may fall behind before it's dropped
+// and backoff is the delay before reconnecting the listener
+func NewHub(source Source, buffer int, backoff time.Duration) *Hub {
+	return &Hub{source: source, buffer: buffer, backoff: backoff, subs: make(map[*Subscription]struct{})}
+}
+
+// Run keeps the listener connected until context is done, subscriptions are dropped with ErrLagged on every reconnect
+// because notifications sent while the listener was disconnected are lost
+func (h *Hub) Run(ctx context.Context) {
+	for {
+		err := h.source.Listen(ctx, h.ready, h.broadcast)
+		h.mu.Lock()
+		h.listening = false
+		h.dropAll(ErrLagged)
+		h.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		logrus.Errorf("Hub -> Run -> %v", err)
+
+		timer := time.NewTimer(h.backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Subscribe returns new subscription to live changes
+func (h *Hub) Subscribe() (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	if !h.listening {
+		return nil, ErrNotListening
+	}
+	s := &Subscription{hub: h, ch: make(chan *model.ProfileChange, h.buffer), since: h.latest}
+	h.subs[s] = struct{}{}
+	return s, nil
+}
+
+// Close drops all subscriptions with ErrClosed and rejects new ones, so watch streams end before server stops
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	h.dropAll(ErrClosed)
+}
+
+// ready remembers the latest sequence once the listener is subscribed
+func (h *Hub) ready(latest int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.listening = true
+	h.latest = latest
+}
+
+// broadcast sends change to every subscription and drops subscriptions that have no room for it
+func (h *Hub) broadcast(change *model.ProfileChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if change.Sequence > h.latest {
+		h.latest = change.Sequence
+	}
+	for s := range h.subs {
+		select {
+		case s.ch <- change:
+		default:
+			h.drop(s, ErrLagged)
+		}
+	}
+}
+
+// dropAll drops every subscription with the reason, it's called with mu locked
+func (h *Hub) dropAll(err error) {
+	for s := range h.subs {
+		h.drop(s, err)
+	}
+}
+
+// drop closes channel of the subscription once, it's called with mu locked
+func (h *Hub) drop(s *Subscription, err error) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.err = err
+	close(s.ch)
+	delete(h.subs, s)
+}
@@ -0,0 +1,120 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource lets the test push notifications and break the connection
+type fakeSource struct {
+	latest  int64
+	changes chan *model.ProfileChange
+	breaks  chan struct{}
+	listens chan struct{}
+}
+
+func newFakeSource(latest int64) *fakeSource {
+	return &fakeSource{latest: latest, changes: make(chan *model.ProfileChange), breaks: make(chan struct{}),
+		listens: make(chan struct{}, 10)}
+}
+
+func (s *fakeSource) Listen(ctx context.Context, ready func(latest int64), notify func(*model.ProfileChange)) error {
+	ready(s.latest)
+	s.listens <- struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.breaks:
+			return errors.New("connection reset")
+		case change := <-s.changes:
+			notify(change)
+		}
+	}
+}
+
+func runHub(t *testing.T, source *fakeSource, buffer int) *Hub {
+	hub := NewHub(source, buffer, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	<-source.listens
+	return hub
+}
+
+func change(seq int64) *model.ProfileChange {
+	return &model.ProfileChange{Sequence: seq, ProfileID: uuid.New(), Operation: "UPDATE", ChangedAt: time.Now()}
+}
+
+func TestHubBroadcasts(t *testing.T) {
+	source := newFakeSource(7)
+	hub := runHub(t, source, 4)
+
+	first, err := hub.Subscribe()
+	require.NoError(t, err)
+	second, err := hub.Subscribe()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), first.Since())
+
+	source.changes <- change(8)
+	require.Equal(t, int64(8), (<-first.C()).Sequence)
+	require.Equal(t, int64(8), (<-second.C()).Sequence)
+
+	third, err := hub.Subscribe()
+	require.NoError(t, err)
+	require.Equal(t, int64(8), third.Since())
+
+	first.Close()
+	_, ok := <-first.C()
+	require.False(t, ok)
+	require.ErrorIs(t, first.Err(), ErrClosed)
+	require.NoError(t, second.Err())
+}
+
+func TestHubDropsLaggedSubscription(t *testing.T) {
+	source := newFakeSource(0)
+	hub := runHub(t, source, 1)
+
+	sub, err := hub.Subscribe()
+	require.NoError(t, err)
+	source.changes <- change(1)
+	source.changes <- change(2)
+
+	require.Equal(t, int64(1), (<-sub.C()).Sequence)
+	_, ok := <-sub.C()
+	require.False(t, ok)
+	require.ErrorIs(t, sub.Err(), ErrLagged)
+}
+
+func TestHubDropsSubscriptionsOnReconnect(t *testing.T) {
+	source := newFakeSource(3)
+	hub := runHub(t, source, 4)
+
+	sub, err := hub.Subscribe()
+	require.NoError(t, err)
+	source.breaks <- struct{}{}
+	_, ok := <-sub.C()
+	require.False(t, ok)
+	require.ErrorIs(t, sub.Err(), ErrLagged)
+
+	<-source.listens
+	_, err = hub.Subscribe()
+	require.NoError(t, err)
+
+	hub.Close()
+	_, err = hub.Subscribe()
+	require.ErrorIs(t, err, ErrClosed)
+}
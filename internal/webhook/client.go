@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errRedirect is returned for redirects, endpoints must answer deliveries themselves
+var errRedirect = errors.New("redirects aren't followed")
+
+// NewHTTPClient returns client for deliveries that connects only to public addresses, so subscriptions can't make
+// the service call its own network, refuses redirects and gives up on requests after timeout
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return newHTTPClient(timeout, isPublic)
+}
+
+// newHTTPClient returns client that dials only addresses accepted by allowed. Addresses are checked after they are
+// resolved, right before the connection, so DNS names that resolve to internal addresses are rejected as well
+func newHTTPClient(timeout time.Duration, allowed func(ip net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("Control -> %w", err)
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("Control -> error: address %s isn't allowed", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errRedirect
+		},
+		Timeout: timeout,
+	}
+}
+
+// isPublic reports whether ip is a public unicast address rather than loopback, private, link-local or unspecified one
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsUnspecified()
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClientRejectsInternalAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewHTTPClient(time.Second).Post(srv.URL, "application/json", strings.NewReader("{}"))
+	require.ErrorContains(t, err, "isn't allowed")
+}
+
+func TestHTTPClientRefusesRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(target.Close)
+	srv := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	t.Cleanup(srv.Close)
+
+	client := newHTTPClient(time.Second, func(net.IP) bool { return true })
+	_, err := client.Post(srv.URL, "application/json", strings.NewReader("{}"))
+	require.ErrorIs(t, err, errRedirect)
+}
+
+func TestIsPublic(t *testing.T) {
+	for address, public := range map[string]bool{
+		"93.184.216.34": true, "2606:2800:220:1::1": true, "127.0.0.1": false, "::1": false, "10.1.2.3": false,
+		"172.16.0.1": false, "192.168.1.1": false, "169.254.169.254": false, "fe80::1": false, "fd00::1": false,
+		"0.0.0.0": false, "::ffff:127.0.0.1": false,
+	} {
+		require.Equal(t, public, isPublic(net.ParseIP(address)), address)
+	}
+}
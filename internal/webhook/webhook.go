@@ -0,0 +1,170 @@
+// Package webhook delivers profile events to partner endpoints as signed HTTP requests
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/outbox"
+	"github.com/distuurbia/profile/internal/poll"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers of every webhook request
+const (
+	SignatureHeader = "X-Profile-Signature"
+	EventIDHeader   = "X-Profile-Event-Id"
+	EventTypeHeader = "X-Profile-Event-Type"
+)
+
+const maxErrorLength = 1024
+
+// Store hands due deliveries to Dispatcher and saves results of their attempts,
+// it's implemented by repository.WebhookRepository
+type Store interface {
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, attempt *model.WebhookAttempt, delivered bool, nextAttemptAt time.Time) error
+}
+
+// Options contains delivery settings of Dispatcher
+type Options struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	MaxAttempts  int
+}
+
+// Dispatcher posts due deliveries to their endpoints and retries failed ones with exponential backoff
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	opts   Options
+	now    func() time.Time
+}
+
+// NewDispatcher creates an object of *Dispatcher
+func NewDispatcher(store Store, client *http.Client, opts Options) *Dispatcher {
+	return &Dispatcher{store: store, client: client, opts: opts, now: time.Now}
+}
+
+// Sign returns value of SignatureHeader: unix timestamp of the request and hex HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with secret of the subscription, receivers should reject requests with old timestamps to prevent replays
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns delay before the next attempt after the given number of failed attempts
+func (d *Dispatcher) Backoff(attempts int32) time.Duration {
+	delay := d.opts.MinBackoff
+	for i := int32(1); i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.opts.MaxBackoff {
+		return d.opts.MaxBackoff
+	}
+	return delay
+}
+
+// Run delivers webhooks until context is done, full batches are sent one after another
+// and otherwise the dispatcher waits for the next poll
+func (d *Dispatcher) Run(ctx context.Context) {
+	poll.Run(ctx, d.opts.PollInterval, d.dispatchBatch)
+}
+
+// dispatchBatch sends one batch of due deliveries and reports whether it was full, so more of them may be waiting
+func (d *Dispatcher) dispatchBatch(ctx context.Context) bool {
+	// deliveries are leased for the longest time their requests may take, so no other replica picks them up meanwhile
+	lease := d.opts.Timeout*time.Duration(d.opts.BatchSize) + d.opts.PollInterval
+	deliveries, err := d.store.ClaimDeliveries(ctx, d.opts.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logrus.Errorf("Dispatcher -> dispatchBatch -> %v", err)
+		}
+		return false
+	}
+	for _, delivery := range deliveries {
+		if err = d.deliver(ctx, delivery); err != nil {
+			if ctx.Err() == nil {
+				logrus.Errorf("Dispatcher -> dispatchBatch -> %v", err)
+			}
+			return false
+		}
+	}
+	return len(deliveries) == d.opts.BatchSize
+}
+
+// deliver makes one attempt of the delivery and saves its result
+func (d *Dispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) error {
+	attempt := model.WebhookAttempt{DeliveryID: delivery.ID, EventID: delivery.Event.ID, EventType: delivery.Event.Type, AttemptedAt: d.now()}
+	statusCode, err := d.post(ctx, delivery, attempt.AttemptedAt)
+	attempt.Duration = d.now().Sub(attempt.AttemptedAt)
+	attempt.StatusCode = int32(statusCode)
+	delivered := err == nil
+	if err != nil {
+		attempt.Error = truncate(err.Error(), maxErrorLength)
+	}
+
+	var nextAttemptAt time.Time
+	attempts := delivery.Attempts + 1
+	if !delivered && int(attempts) < d.opts.MaxAttempts {
+		nextAttemptAt = d.now().Add(d.Backoff(attempts))
+	}
+	err = d.store.RecordAttempt(ctx, &attempt, delivered, nextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("Dispatcher -> deliver -> %w", err)
+	}
+	return nil
+}
+
+// post sends signed envelope of the event and returns status code of the response, any status other than 2xx is an error
+func (d *Dispatcher) post(ctx context.Context, delivery *model.WebhookDelivery, timestamp time.Time) (int, error) {
+	body, err := outbox.Encode(&delivery.Event)
+	if err != nil {
+		return 0, fmt.Errorf("post -> %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("post -> %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+	req.Header.Set(EventIDHeader, strconv.FormatInt(delivery.Event.ID, 10))
+	req.Header.Set(EventTypeHeader, delivery.Event.Type)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post -> %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("post -> unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// truncate cuts s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
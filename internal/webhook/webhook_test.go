@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/model"
+	"github.com/distuurbia/profile/internal/outbox"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "whsec_test"
+
+// memoryStore keeps deliveries in memory and behaves like repository.WebhookRepository
+type memoryStore struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	deliveries []*model.WebhookDelivery
+	next       map[int64]time.Time
+	done       map[int64]bool
+	attempts   []*model.WebhookAttempt
+}
+
+func newMemoryStore(now func() time.Time, deliveries ...*model.WebhookDelivery) *memoryStore {
+	return &memoryStore{now: now, deliveries: deliveries, next: map[int64]time.Time{}, done: map[int64]bool{}}
+}
+
+func (s *memoryStore) ClaimDeliveries(_ context.Context, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []*model.WebhookDelivery
+	for _, d := range s.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if s.done[d.ID] || s.next[d.ID].After(s.now()) {
+			continue
+		}
+		s.next[d.ID] = s.now().Add(lease)
+		copied := *d
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) RecordAttempt(_ context.Context, attempt *model.WebhookAttempt, delivered bool, nextAttemptAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts = append(s.attempts, attempt)
+	for _, d := range s.deliveries {
+		if d.ID == attempt.DeliveryID {
+			d.Attempts++
+		}
+	}
+	s.done[attempt.DeliveryID] = delivered || nextAttemptAt.IsZero()
+	s.next[attempt.DeliveryID] = nextAttemptAt
+	return nil
+}
+
+func testDelivery(url string) *model.WebhookDelivery {
+	return &model.WebhookDelivery{ID: 1, SubscriptionID: uuid.New(), URL: url, Secret: testSecret, Event: model.OutboxEvent{
+		ID: 9, Type: model.EventProfileDeleted, ProfileID: uuid.New(), Payload: []byte(`{}`), CreatedAt: time.Now().UTC(),
+	}}
+}
+
+func testOptions() Options {
+	return Options{BatchSize: 10, PollInterval: time.Second, Timeout: time.Second, MinBackoff: time.Second, MaxBackoff: 4 * time.Second,
+		MaxAttempts: 3}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	delivery := testDelivery(server.URL)
+	store := newMemoryStore(func() time.Time { return now }, delivery)
+	d := NewDispatcher(store, server.Client(), testOptions())
+	d.now = store.now
+
+	require.False(t, d.dispatchBatch(context.Background()))
+
+	expected, err := outbox.Encode(&delivery.Event)
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(body))
+	require.Equal(t, Sign(testSecret, now, body), header.Get(SignatureHeader))
+	require.True(t, strings.HasPrefix(header.Get(SignatureHeader), "t="))
+	require.Equal(t, "9", header.Get(EventIDHeader))
+	require.Equal(t, model.EventProfileDeleted, header.Get(EventTypeHeader))
+	require.Len(t, store.attempts, 1)
+	require.Equal(t, int32(http.StatusNoContent), store.attempts[0].StatusCode)
+	require.True(t, store.done[delivery.ID])
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now()
+	delivery := testDelivery(server.URL)
+	store := newMemoryStore(func() time.Time { return now }, delivery)
+	d := NewDispatcher(store, server.Client(), testOptions())
+	d.now = store.now
+
+	d.dispatchBatch(context.Background())
+	require.Equal(t, now.Add(time.Second), store.next[delivery.ID])
+	d.dispatchBatch(context.Background())
+	require.Equal(t, 1, calls)
+
+	now = now.Add(time.Second)
+	d.dispatchBatch(context.Background())
+	require.Equal(t, now.Add(2*time.Second), store.next[delivery.ID])
+
+	now = now.Add(2 * time.Second)
+	d.dispatchBatch(context.Background())
+	require.Equal(t, 3, calls)
+	require.True(t, store.done[delivery.ID])
+	require.Len(t, store.attempts, 3)
+	require.Equal(t, int32(http.StatusServiceUnavailable), store.attempts[2].StatusCode)
+	require.Contains(t, store.attempts[2].Error, "503")
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, nil, testOptions())
+	require.Equal(t, time.Second, d.Backoff(1))
+	require.Equal(t, 2*time.Second, d.Backoff(2))
+	require.Equal(t, 4*time.Second, d.Backoff(3))
+	require.Equal(t, 4*time.Second, d.Backoff(30))
+}
@@ -3,26 +3,69 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/caarlos0/env"
+	"github.com/distuurbia/profile/internal/audit"
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/distuurbia/profile/internal/cache"
 	"github.com/distuurbia/profile/internal/config"
 	"github.com/distuurbia/profile/internal/handler"
+	"github.com/distuurbia/profile/internal/health"
+	"github.com/distuurbia/profile/internal/logging"
+	"github.com/distuurbia/profile/internal/metrics"
+	"github.com/distuurbia/profile/internal/mtls"
+	"github.com/distuurbia/profile/internal/outbox"
+	"github.com/distuurbia/profile/internal/poll"
+	"github.com/distuurbia/profile/internal/ratelimit"
+	"github.com/distuurbia/profile/internal/redact"
 	"github.com/distuurbia/profile/internal/repository"
+	"github.com/distuurbia/profile/internal/repository/memory"
+	"github.com/distuurbia/profile/internal/repository/sqlite"
 	"github.com/distuurbia/profile/internal/service"
+	"github.com/distuurbia/profile/internal/tracing"
+	"github.com/distuurbia/profile/internal/watch"
+	"github.com/distuurbia/profile/internal/webhook"
 	protocol "github.com/distuurbia/profile/protocol/profile"
 	"github.com/go-playground/validator"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/netutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 )
 
-func connectPostgres(cfg *config.Config) (*pgxpool.Pool, error) {
-	conf, err := pgxpool.ParseConfig(cfg.PostgresPath)
+const httpReadHeaderTimeout = 5 * time.Second
+
+// connectPostgres connects to Postgres at path, the primary and replicas share settings of the pool
+func connectPostgres(cfg *config.Config, path string, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
+	conf, err := pgxpool.ParseConfig(path)
 	if err != nil {
 		return nil, fmt.Errorf("error in method pgxpool.ParseConfig: %v", err)
 	}
+	conf.MinConns = int32(cfg.PostgresMinConns)
+	conf.MaxConns = int32(cfg.PostgresMaxConns)
+	conf.MaxConnLifetime = cfg.PostgresMaxConnLifetime
+	conf.MaxConnIdleTime = cfg.PostgresMaxConnIdleTime
+	conf.HealthCheckPeriod = cfg.PostgresHealthCheckPeriod
+	if cfg.PostgresStatementTimeout > 0 {
+		conf.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.PostgresStatementTimeout.Milliseconds(), 10)
+	}
+	conf.ConnConfig.Tracer = tracer
 	pool, err := pgxpool.NewWithConfig(context.Background(), conf)
 	if err != nil {
 		return nil, fmt.Errorf("error in method pgxpool.NewWithConfig: %v", err)
@@ -30,27 +73,446 @@ func connectPostgres(cfg *config.Config) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
+// storage contains repositories of the configured backend, pool is nil when data isn't kept in Postgres
+// and replicas are nil unless Postgres has read replicas
+type storage struct {
+	pool     *pgxpool.Pool
+	replicas *repository.Replicas
+	db       health.Pinger
+	profiles service.ProfileRepository
+	changes  changeLog
+	close    func()
+}
+
+// changeLog notifies about changes of profiles and drops old ones
+type changeLog interface {
+	watch.Source
+	DeleteChanges(ctx context.Context, age time.Duration) error
+}
+
+// openStorage connects to Postgres with its read replicas and registers metrics of the primary pool, opens SQLite file
+// applying its migrations, or creates empty repository in memory
+func openStorage(cfg *config.Config, tracer pgx.QueryTracer, m *metrics.Metrics) (*storage, error) {
+	switch cfg.RepositoryBackend {
+	case "memory":
+		r := memory.NewProfileRepository()
+		return &storage{db: r, profiles: r, changes: r}, nil
+	case "sqlite":
+		r, err := sqlite.Open(context.Background(), cfg.SQLitePath)
+		if err != nil {
+			return nil, fmt.Errorf("openStorage -> %w", err)
+		}
+		return &storage{db: r, profiles: r, changes: r, close: func() {
+			if err := r.Close(); err != nil {
+				logrus.Errorf("main -> storage.Close -> %v", err)
+			}
+		}}, nil
+	}
+	pool, err := connectPostgres(cfg, cfg.PostgresPath, tracer)
+	if err != nil {
+		return nil, fmt.Errorf("openStorage -> %w", err)
+	}
+	if err = m.Register(metrics.NewPoolCollector(pool)); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("openStorage -> %w", err)
+	}
+	st := &storage{pool: pool, db: pool, profiles: repository.NewProfileRepository(pool), changes: repository.NewChangeRepository(pool),
+		close: pool.Close}
+	if len(cfg.PostgresReplicaPaths) == 0 {
+		return st, nil
+	}
+
+	replicaPools := make([]*pgxpool.Pool, 0, len(cfg.PostgresReplicaPaths))
+	for _, path := range cfg.PostgresReplicaPaths {
+		replicaPool, err := connectPostgres(cfg, path, tracer)
+		if err != nil {
+			for _, opened := range replicaPools {
+				opened.Close()
+			}
+			pool.Close()
+			return nil, fmt.Errorf("openStorage -> %w", err)
+		}
+		replicaPools = append(replicaPools, replicaPool)
+	}
+	st.replicas = repository.NewReplicas(pool, replicaPools, cfg.PostgresReplicaMaxLag, cfg.PostgresReplicaCheckTimeout)
+	st.profiles = repository.NewReplicatedProfileRepository(st.replicas)
+	st.close = func() {
+		st.replicas.Close()
+		pool.Close()
+	}
+	return st, nil
+}
+
+// Close closes Postgres pool or SQLite database if there is one
+func (s *storage) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// listen opens listener of gRPC server that accepts no more than GRPCMaxConnections connections at once
+func listen(cfg *config.Config) (net.Listener, error) {
+	lis, err := net.Listen("tcp", cfg.GRPCAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error in method net.Listen: %v", err)
+	}
+	if cfg.GRPCMaxConnections > 0 {
+		lis = netutil.LimitListener(lis, cfg.GRPCMaxConnections)
+	}
+	return lis, nil
+}
+
+// serverOptions returns message size, stream and keepalive limits of gRPC server from config
+func serverOptions(cfg *config.Config) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.GRPCMaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.GRPCMaxSendMsgSize),
+		grpc.MaxConcurrentStreams(uint32(cfg.GRPCMaxConcurrentStreams)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:                  cfg.GRPCKeepaliveTime,
+			Timeout:               cfg.GRPCKeepaliveTimeout,
+			MaxConnectionIdle:     cfg.GRPCMaxConnectionIdle,
+			MaxConnectionAge:      cfg.GRPCMaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.GRPCMaxConnectionAgeGrace,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.GRPCKeepaliveMinTime,
+			PermitWithoutStream: cfg.GRPCKeepalivePermitWithoutStream,
+		}),
+	}
+}
+
+// transportCredentials returns TLS credentials of gRPC server that reload certificates in background,
+// the server stays plaintext if no certificate is configured
+func transportCredentials(ctx context.Context, cfg *config.Config, workers *sync.WaitGroup) (grpc.ServerOption, error) {
+	if cfg.TLSCertFile == "" {
+		return grpc.Creds(insecure.NewCredentials()), nil
+	}
+	reloader, err := mtls.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("transportCredentials -> %w", err)
+	}
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		reloader.Run(ctx, cfg.TLSReloadInterval)
+	}()
+	return grpc.Creds(credentials.NewTLS(reloader.ServerConfig())), nil
+}
+
+// authInterceptors returns interceptors that check bearer tokens against policies of ProfileService,
+// health checks stay public for probes
+func authInterceptors(cfg *config.Config) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor, error) {
+	var publicKeys map[string]ed25519.PublicKey
+	if cfg.JWTJWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.JWTJWKSFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("authInterceptors -> %w", err)
+		}
+		publicKeys = keys
+	}
+	verifier := auth.NewVerifier([]byte(cfg.SecretKey), publicKeys, cfg.JWTIssuer, cfg.JWTAudience, cfg.JWTLeeway)
+	policies := handler.Policies()
+	policies["/"+healthpb.Health_ServiceDesc.ServiceName+"/Check"] = auth.Public()
+	policies["/"+healthpb.Health_ServiceDesc.ServiceName+"/Watch"] = auth.Public()
+	return auth.UnaryServerInterceptor(verifier, policies), auth.StreamServerInterceptor(verifier, policies), nil
+}
+
+// rateLimiter creates limiter with buckets in memory of this replica or in postgres shared by all replicas,
+// buckets in postgres are cleaned up in background
+func rateLimiter(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool, workers *sync.WaitGroup) (*ratelimit.Limiter, error) {
+	policies, err := ratelimit.ParsePolicies(cfg.RateLimits)
+	if err != nil {
+		return nil, fmt.Errorf("rateLimiter -> %w", err)
+	}
+	if cfg.RateLimitStore == "memory" {
+		return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policies, cfg.RateLimitFailOpen), nil
+	}
+
+	store := repository.NewRateLimitRepository(pool, service.DeriveKey(cfg.SecretKey, service.RateLimitKeyLabel))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		poll.Run(ctx, cfg.RateLimitCleanupInterval, func(ctx context.Context) bool {
+			if err := store.DeleteIdle(ctx); err != nil {
+				logrus.Errorf("main -> rateLimiter -> %v", err)
+			}
+			return false
+		})
+	}()
+	return ratelimit.NewLimiter(store, policies, cfg.RateLimitFailOpen), nil
+}
+
+// outboxRelay starts worker that publishes profile lifecycle events from the outbox to JetStream and returns function
+// closing connection to the broker, with none publisher events are kept for OUTBOX_RETENTION and then deleted
+func outboxRelay(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool, workers *sync.WaitGroup) (func(), error) {
+	if cfg.OutboxPublisher == "none" {
+		if pool != nil {
+			outboxCleanup(ctx, cfg, repository.NewOutboxRepository(pool), workers)
+		}
+		return func() {}, nil
+	}
+	conn, err := nats.Connect(cfg.NATSURL, nats.Name("profile"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("outboxRelay -> %w", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("outboxRelay -> %w", err)
+	}
+	publisher := outbox.NewNATSPublisher(js, cfg.NATSSubjectPrefix, cfg.OutboxPublishTimeout)
+	relay := outbox.NewRelay(repository.NewOutboxRepository(pool), publisher, cfg.OutboxBatchSize, cfg.OutboxPollInterval)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(ctx)
+	}()
+	return conn.Close, nil
+}
+
+// outboxCleanup starts worker that deletes events nobody publishes, so the outbox doesn't grow without a publisher
+func outboxCleanup(ctx context.Context, cfg *config.Config, store *repository.OutboxRepository, workers *sync.WaitGroup) {
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		poll.Run(ctx, cfg.OutboxCleanupInterval, func(ctx context.Context) bool {
+			if err := store.DeleteEvents(ctx, cfg.OutboxRetention); err != nil {
+				logrus.Errorf("main -> outboxCleanup -> %v", err)
+			}
+			return false
+		})
+	}()
+}
+
+// cachedRepository wraps the repository with read-through cache of the configured backend and returns function closing
+// connection to Redis, with none backend every read goes to Postgres
+func cachedRepository(cfg *config.Config, r service.ProfileRepository) (service.ProfileRepository, func(), error) {
+	opts := cache.Options{Prefix: cfg.CacheKeyPrefix, TTL: cfg.CacheTTL, NegativeTTL: cfg.CacheNegativeTTL,
+		LoadTimeout: cfg.CacheLoadTimeout}
+	switch cfg.CacheBackend {
+	case "memory":
+		return cache.NewCachedRepository(r, cache.NewLRUBackend(cfg.CacheSize), opts), func() {}, nil
+	case "redis":
+		redisOpts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cachedRepository -> %w", err)
+		}
+		client := redis.NewClient(redisOpts)
+		closeClient := func() {
+			if err := client.Close(); err != nil {
+				logrus.Errorf("main -> cachedRepository -> %v", err)
+			}
+		}
+		return cache.NewCachedRepository(r, cache.NewRedisBackend(client), opts), closeClient, nil
+	}
+	return r, func() {}, nil
+}
+
+// webhookDispatcher starts worker that delivers profile events to webhook subscriptions only at public addresses
+// and worker deleting finished deliveries older than retention
+func webhookDispatcher(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool, workers *sync.WaitGroup) {
+	store := repository.NewWebhookRepository(pool)
+	dispatcher := webhook.NewDispatcher(store, webhook.NewHTTPClient(cfg.WebhookTimeout), webhook.Options{
+		BatchSize:    cfg.WebhookBatchSize,
+		PollInterval: cfg.WebhookPollInterval,
+		Timeout:      cfg.WebhookTimeout,
+		MinBackoff:   cfg.WebhookMinBackoff,
+		MaxBackoff:   cfg.WebhookMaxBackoff,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+	})
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		poll.Run(ctx, cfg.WebhookCleanupInterval, func(ctx context.Context) bool {
+			if err := store.DeleteDeliveries(ctx, cfg.WebhookRetention); err != nil {
+				logrus.Errorf("main -> webhookDispatcher -> %v", err)
+			}
+			return false
+		})
+	}()
+}
+
+// changeFeed starts listener of profile changes with worker deleting changes older than retention
+func changeFeed(ctx context.Context, cfg *config.Config, changes changeLog, workers *sync.WaitGroup) *watch.Hub {
+	hub := watch.NewHub(changes, cfg.WatchBufferSize, cfg.WatchReconnectBackoff)
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		hub.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		poll.Run(ctx, cfg.ProfileChangesCleanupInterval, func(ctx context.Context) bool {
+			if err := changes.DeleteChanges(ctx, cfg.ProfileChangesRetention); err != nil {
+				logrus.Errorf("main -> changeFeed -> %v", err)
+			}
+			return false
+		})
+	}()
+	return hub
+}
+
+// gracefulStop waits for in-flight calls until timeout and then cancels the rest of them
+func gracefulStop(server *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.C:
+		logrus.Warnf("main -> gracefulStop -> in-flight calls weren't finished in %s, cancelling them", timeout)
+		server.Stop()
+		<-stopped
+	}
+}
+
 func main() {
-	var cfg config.Config
-	if err := env.Parse(&cfg); err != nil {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.AddHook(redact.NewHook())
+	cfg, err := config.Load()
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	tp, err := tracing.NewTracerProvider(ctx, cfg)
+	if err != nil {
 		logrus.Fatalf("main -> %v", err)
 	}
-	pool, err := connectPostgres(&cfg)
+	m := metrics.New()
+	st, err := openStorage(cfg, tracing.ChainQueryTracers(tracing.QueryTracer(tp), m.QueryTracer()), m)
 	if err != nil {
 		logrus.Fatalf("main -> %v", err)
 	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	checker := health.NewChecker(st.db, cfg.HealthCheckInterval, cfg.HealthCheckTimeout, protocol.ProfileService_ServiceDesc.ServiceName)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		checker.Run(workersCtx)
+	}()
+	if st.replicas != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			st.replicas.Run(workersCtx, cfg.PostgresReplicaCheckInterval)
+		}()
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	mux.Handle("/healthz", checker.LivenessHandler())
+	mux.Handle("/readyz", checker.ReadinessHandler())
+	httpServer := &http.Server{Addr: cfg.HTTPAddress, Handler: mux, ReadHeaderTimeout: httpReadHeaderTimeout}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatalf("main -> %v", err)
+		}
+	}()
+
 	validate := validator.New()
-	r := repository.NewProfileRepository(pool)
-	s := service.NewProfileService(r, &cfg)
+	r, closeCache, err := cachedRepository(cfg, st.profiles)
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	s := service.NewProfileService(r, cfg)
+	hub := changeFeed(workersCtx, cfg, st.changes, &workers)
+	s.SetChangeFeed(hub)
 	h := handler.NewProfileHandler(s, validate)
-	lis, err := net.Listen("tcp", "localhost:8083")
+	lis, err := listen(cfg)
 	if err != nil {
 		logrus.Fatalf("main -> %v", err)
 	}
-	serverRegistrar := grpc.NewServer()
-	protocol.RegisterProfileServiceServer(serverRegistrar, h)
-	err = serverRegistrar.Serve(lis)
+	creds, err := transportCredentials(workersCtx, cfg, &workers)
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	rules, err := mtls.ParseRules(cfg.TLSAllowedClients)
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	authUnary, authStream, err := authInterceptors(cfg)
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	limiter, err := rateLimiter(workersCtx, cfg, st.pool, &workers)
 	if err != nil {
 		logrus.Fatalf("main -> %v", err)
 	}
+	closeOutbox, err := outboxRelay(workersCtx, cfg, st.pool, &workers)
+	if err != nil {
+		logrus.Fatalf("main -> %v", err)
+	}
+	// memory and sqlite repositories don't queue webhook deliveries, so there is nothing to dispatch
+	// and webhook methods are rejected
+	if st.pool != nil {
+		webhookDispatcher(workersCtx, cfg, st.pool, &workers)
+		s.EnableWebhooks()
+	}
+	serverRegistrar := grpc.NewServer(append(serverOptions(cfg), creds,
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryServerInterceptor(tp),
+			m.UnaryServerInterceptor(),
+			logging.UnaryServerInterceptor(logrus.StandardLogger()),
+			mtls.UnaryServerInterceptor(rules),
+			authUnary,
+			limiter.UnaryServerInterceptor(),
+			audit.UnaryServerInterceptor(),
+			handler.PermissionCacheInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			tracing.StreamServerInterceptor(tp),
+			m.StreamServerInterceptor(),
+			logging.StreamServerInterceptor(logrus.StandardLogger()),
+			mtls.StreamServerInterceptor(rules),
+			authStream,
+			limiter.StreamServerInterceptor(),
+			audit.StreamServerInterceptor(),
+		),
+	)...)
+	protocol.RegisterProfileServiceServer(serverRegistrar, h)
+	healthpb.RegisterHealthServer(serverRegistrar, checker.Server())
+	served := make(chan error, 1)
+	go func() {
+		served <- serverRegistrar.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.Info("main -> shutting down")
+	case err = <-served:
+		logrus.Errorf("main -> %v", err)
+	}
+	stop()
+
+	// probes report NOT_SERVING first, so no new calls are routed here while in-flight ones are drained
+	checker.Shutdown()
+	// watch streams never end on their own, they are closed so graceful stop doesn't wait for them
+	hub.Close()
+	gracefulStop(serverRegistrar, cfg.ShutdownTimeout)
+	stopWorkers()
+	workers.Wait()
+	closeOutbox()
+	closeCache()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err = httpServer.Shutdown(shutdownCtx); err != nil {
+		logrus.Errorf("main -> %v", err)
+	}
+	if err = tp.Shutdown(shutdownCtx); err != nil {
+		logrus.Errorf("main -> %v", err)
+	}
+	st.Close()
 }
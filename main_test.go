@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distuurbia/profile/internal/auth"
+	"github.com/distuurbia/profile/internal/config"
+	protocol "github.com/distuurbia/profile/protocol/profile"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptorsAcceptTokensSignedWithSecretKey(t *testing.T) {
+	cfg := &config.Config{SecretKey: "secret", JWTLeeway: time.Second}
+	unary, _, err := authInterceptors(cfg)
+	require.NoError(t, err)
+
+	call := func(secret string) error {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "issuer", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+			Scope:            auth.ScopeService,
+		}).SignedString([]byte(secret))
+		require.NoError(t, err)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		info := &grpc.UnaryServerInfo{FullMethod: "/" + protocol.ProfileService_ServiceDesc.ServiceName + "/CreateProfile"}
+		_, err = unary(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+	require.NoError(t, call(cfg.SecretKey))
+	require.Equal(t, codes.Unauthenticated, status.Code(call("other secret")))
+}
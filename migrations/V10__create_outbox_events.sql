@@ -0,0 +1,10 @@
+-- Create outbox_events table with profile lifecycle events waiting to be published, relay deletes
+-- an event once it's delivered
+create table outbox_events (
+	id BIGSERIAL,
+	event_type VARCHAR(64) not null,
+	profile_id uuid not null,
+	payload JSONB not null,
+	created_at TIMESTAMPTZ not null default now(),
+	primary key (id)
+);
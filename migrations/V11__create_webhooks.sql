@@ -0,0 +1,40 @@
+-- Create webhook subscriptions, deliveries of profile events to them and attempts of every delivery.
+-- secret is kept in plain text because it signs every request
+create table webhook_subscriptions (
+	id uuid,
+	url VARCHAR(2048) not null,
+	event_types VARCHAR(64)[] not null,
+	secret VARCHAR(255) not null,
+	created_at TIMESTAMPTZ not null default now(),
+	primary key (id)
+);
+
+create table webhook_deliveries (
+	id BIGSERIAL,
+	subscription_id uuid not null references webhook_subscriptions (id) on delete cascade,
+	event_id BIGINT not null,
+	event_type VARCHAR(64) not null,
+	profile_id uuid not null,
+	payload JSONB not null,
+	occurred_at TIMESTAMPTZ not null,
+	attempts INT not null default 0,
+	next_attempt_at TIMESTAMPTZ not null default now(),
+	delivered_at TIMESTAMPTZ,
+	failed_at TIMESTAMPTZ,
+	primary key (id)
+);
+
+create index webhook_deliveries_pending_idx on webhook_deliveries (next_attempt_at)
+	where delivered_at is null and failed_at is null;
+
+create table webhook_attempts (
+	id BIGSERIAL,
+	delivery_id BIGINT not null references webhook_deliveries (id) on delete cascade,
+	status_code INT not null,
+	error TEXT not null default '',
+	duration_ms INT not null,
+	attempted_at TIMESTAMPTZ not null default now(),
+	primary key (id)
+);
+
+create index webhook_attempts_delivery_id_idx on webhook_attempts (delivery_id);
@@ -0,0 +1,42 @@
+-- Create profile_changes log filled by trigger on profiles table and notify listeners of every change.
+-- Writers take transaction-level advisory lock before taking the sequence, so changes are committed
+-- in the order of their sequences and watchers resuming after seq never skip a change committed later
+create table profile_changes (
+	seq BIGSERIAL,
+	profile_id uuid not null,
+	operation VARCHAR(16) not null,
+	changed_at TIMESTAMPTZ not null default now(),
+	primary key (seq)
+);
+
+create index profile_changes_changed_at_idx on profile_changes (changed_at);
+
+create function profile_changes_notify() returns trigger as $$
+declare
+	change profile_changes;
+begin
+	perform pg_advisory_xact_lock(hashtext('profile_changes'));
+	insert into profile_changes (profile_id, operation)
+		values (case when TG_OP = 'DELETE' then OLD.id else NEW.id end, TG_OP)
+		returning * into change;
+	perform pg_notify('profile_changes', json_build_object(
+		'seq', change.seq,
+		'id', change.profile_id,
+		'op', change.operation,
+		'at', change.changed_at
+	)::text);
+	return null;
+end;
+$$ language plpgsql;
+
+create trigger profiles_changes_insert_delete after insert or delete on profiles
+	for each row execute function profile_changes_notify();
+
+-- password and refresh token aren't watched, they aren't part of the profile that is cached
+create trigger profiles_changes_update after update on profiles
+	for each row when (
+		(OLD.username, OLD.country, OLD.age, OLD.email, OLD.email_verified_at, OLD.phone, OLD.phone_verified_at)
+		is distinct from
+		(NEW.username, NEW.country, NEW.age, NEW.email, NEW.email_verified_at, NEW.phone, NEW.phone_verified_at)
+	)
+	execute function profile_changes_notify();
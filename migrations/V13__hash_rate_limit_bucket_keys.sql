@@ -0,0 +1,15 @@
+-- Recreate rate_limit_buckets with HMAC of bucket keys instead of addresses, usernames, emails and phones of callers,
+-- and with time at which every bucket is full again, so idle buckets are deleted as soon as they don't throttle anyone.
+-- Buckets are transient, existing ones are dropped
+drop table rate_limit_buckets;
+
+create table rate_limit_buckets (
+	key_hash BYTEA,
+	tokens DOUBLE PRECISION,
+	allowed BOOLEAN,
+	updated_at TIMESTAMPTZ,
+	full_at TIMESTAMPTZ,
+	primary key (key_hash)
+);
+
+create index rate_limit_buckets_full_at_idx on rate_limit_buckets (full_at);
@@ -0,0 +1,14 @@
+-- Keep sequence of the latest deleted change, sequences have gaps left by rolled back transactions,
+-- so the oldest kept change doesn't tell whether watchers resuming after seq missed deleted changes.
+-- Changes deleted before this migration are assumed to end right before the oldest kept one
+create table profile_changes_horizon (
+	id BOOLEAN not null default true check (id),
+	seq BIGINT not null,
+	primary key (id)
+);
+
+insert into profile_changes_horizon (seq)
+	select coalesce(
+		(select min(seq) - 1 from profile_changes),
+		(select case when is_called then last_value else 0 end from profile_changes_seq_seq)
+	);
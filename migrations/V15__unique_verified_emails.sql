@@ -0,0 +1,4 @@
+-- Only verified emails are unique, otherwise anyone could claim address of someone else first
+-- and its owner couldn't register or set it
+drop index profiles_email_key;
+create unique index profiles_email_key on profiles (email) where email_verified_at is not null;
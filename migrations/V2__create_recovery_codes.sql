@@ -0,0 +1,7 @@
+-- Create recovery_codes table for single-use 2FA recovery codes
+create table recovery_codes (
+	profile_id uuid references profiles (id) on delete cascade,
+	code_hash BYTEA,
+	used_at TIMESTAMP,
+	primary key (profile_id, code_hash)
+);
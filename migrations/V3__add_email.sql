@@ -0,0 +1,12 @@
+-- Add email with its verification to profiles table
+alter table profiles add column email VARCHAR;
+alter table profiles add column email_verified_at TIMESTAMPTZ;
+create unique index profiles_email_key on profiles (email);
+
+create table email_verification_tokens (
+	token_hash BYTEA,
+	profile_id uuid references profiles (id) on delete cascade,
+	email VARCHAR,
+	expires_at TIMESTAMPTZ,
+	primary key (token_hash)
+);
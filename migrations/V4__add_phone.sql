@@ -0,0 +1,13 @@
+-- Add phone with its verification to profiles table
+alter table profiles add column phone VARCHAR;
+alter table profiles add column phone_verified_at TIMESTAMPTZ;
+create unique index profiles_phone_key on profiles (phone);
+
+create table phone_verification_codes (
+	profile_id uuid references profiles (id) on delete cascade,
+	phone VARCHAR,
+	code_hash BYTEA,
+	attempts_left INTEGER,
+	expires_at TIMESTAMPTZ,
+	primary key (profile_id)
+);
@@ -0,0 +1,7 @@
+-- Create login_tokens table for single-use passwordless login
+create table login_tokens (
+	token_hash BYTEA,
+	profile_id uuid references profiles (id) on delete cascade,
+	expires_at TIMESTAMPTZ,
+	primary key (token_hash)
+);
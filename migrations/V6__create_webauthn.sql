@@ -0,0 +1,22 @@
+-- Create tables for WebAuthn credentials and state of their ceremonies
+create table webauthn_credentials (
+	id BYTEA,
+	profile_id uuid references profiles (id) on delete cascade,
+	public_key BYTEA,
+	sign_count BIGINT,
+	transports VARCHAR[],
+	aaguid BYTEA,
+	attestation_type VARCHAR,
+	created_at TIMESTAMPTZ default now(),
+	last_used_at TIMESTAMPTZ,
+	primary key (id)
+);
+create index webauthn_credentials_profile_id_idx on webauthn_credentials (profile_id);
+
+create table webauthn_sessions (
+	profile_id uuid references profiles (id) on delete cascade,
+	ceremony VARCHAR,
+	session_data BYTEA,
+	expires_at TIMESTAMPTZ,
+	primary key (profile_id, ceremony)
+);
@@ -0,0 +1,28 @@
+-- Create roles, their permissions and roles assigned to profiles
+create table roles (
+	name VARCHAR(64),
+	description VARCHAR(255),
+	primary key (name)
+);
+
+create table role_permissions (
+	role VARCHAR(64) references roles (name) on delete cascade,
+	permission VARCHAR(128),
+	primary key (role, permission)
+);
+
+create table profile_roles (
+	profile_id uuid references profiles (id) on delete cascade,
+	role VARCHAR(64) references roles (name) on delete cascade,
+	granted_at TIMESTAMPTZ default now(),
+	primary key (profile_id, role)
+);
+
+insert into roles (name, description) values
+	('admin', 'Full access to every profile'),
+	('user', 'Access to own profile');
+
+insert into role_permissions (role, permission) values
+	('admin', '*'),
+	('user', 'profile.read'),
+	('user', 'profile.update');
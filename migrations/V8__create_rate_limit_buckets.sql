@@ -0,0 +1,10 @@
+-- Create rate_limit_buckets table with token buckets shared by all replicas
+create table rate_limit_buckets (
+	key VARCHAR(512),
+	tokens DOUBLE PRECISION,
+	allowed BOOLEAN,
+	updated_at TIMESTAMPTZ,
+	primary key (key)
+);
+
+create index rate_limit_buckets_updated_at_idx on rate_limit_buckets (updated_at);
@@ -0,0 +1,24 @@
+-- Create append-only audit_events table with every mutation of profiles, target_id has no foreign key
+-- so events outlive deleted profiles
+create table audit_events (
+	id BIGSERIAL,
+	actor VARCHAR(255) not null,
+	method VARCHAR(255) not null,
+	action VARCHAR(64) not null,
+	target_id uuid not null,
+	changes JSONB not null default '{}',
+	created_at TIMESTAMPTZ not null default now(),
+	primary key (id)
+);
+
+create index audit_events_target_id_idx on audit_events (target_id, id);
+create index audit_events_created_at_idx on audit_events (created_at);
+
+create function audit_events_append_only() returns trigger as $$
+begin
+	raise exception 'audit_events is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_events_append_only before update or delete or truncate on audit_events
+	for each statement execute function audit_events_append_only();
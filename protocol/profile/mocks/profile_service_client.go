@@ -50,6 +50,237 @@ func (_m *ProfileServiceClient) AddRefreshToken(ctx context.Context, in *profile
 	return r0, r1
 }
 
+// AssignRole provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) AssignRole(ctx context.Context, in *profile.AssignRoleRequest, opts ...grpc.CallOption) (*profile.AssignRoleResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.AssignRoleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.AssignRoleRequest, ...grpc.CallOption) (*profile.AssignRoleResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.AssignRoleRequest, ...grpc.CallOption) *profile.AssignRoleResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.AssignRoleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.AssignRoleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginWebAuthnLogin provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) BeginWebAuthnLogin(ctx context.Context, in *profile.BeginWebAuthnLoginRequest, opts ...grpc.CallOption) (*profile.BeginWebAuthnLoginResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.BeginWebAuthnLoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.BeginWebAuthnLoginRequest, ...grpc.CallOption) (*profile.BeginWebAuthnLoginResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.BeginWebAuthnLoginRequest, ...grpc.CallOption) *profile.BeginWebAuthnLoginResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.BeginWebAuthnLoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.BeginWebAuthnLoginRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginWebAuthnRegistration provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) BeginWebAuthnRegistration(ctx context.Context, in *profile.BeginWebAuthnRegistrationRequest, opts ...grpc.CallOption) (*profile.BeginWebAuthnRegistrationResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.BeginWebAuthnRegistrationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.BeginWebAuthnRegistrationRequest, ...grpc.CallOption) (*profile.BeginWebAuthnRegistrationResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.BeginWebAuthnRegistrationRequest, ...grpc.CallOption) *profile.BeginWebAuthnRegistrationResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.BeginWebAuthnRegistrationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.BeginWebAuthnRegistrationRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckPermission provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) CheckPermission(ctx context.Context, in *profile.CheckPermissionRequest, opts ...grpc.CallOption) (*profile.CheckPermissionResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.CheckPermissionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.CheckPermissionRequest, ...grpc.CallOption) (*profile.CheckPermissionResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.CheckPermissionRequest, ...grpc.CallOption) *profile.CheckPermissionResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.CheckPermissionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.CheckPermissionRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmEmail provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ConfirmEmail(ctx context.Context, in *profile.ConfirmEmailRequest, opts ...grpc.CallOption) (*profile.ConfirmEmailResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ConfirmEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConfirmEmailRequest, ...grpc.CallOption) (*profile.ConfirmEmailResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConfirmEmailRequest, ...grpc.CallOption) *profile.ConfirmEmailResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ConfirmEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ConfirmEmailRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmPhone provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ConfirmPhone(ctx context.Context, in *profile.ConfirmPhoneRequest, opts ...grpc.CallOption) (*profile.ConfirmPhoneResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ConfirmPhoneResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConfirmPhoneRequest, ...grpc.CallOption) (*profile.ConfirmPhoneResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConfirmPhoneRequest, ...grpc.CallOption) *profile.ConfirmPhoneResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ConfirmPhoneResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ConfirmPhoneRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ConsumeRecoveryCode(ctx context.Context, in *profile.ConsumeRecoveryCodeRequest, opts ...grpc.CallOption) (*profile.ConsumeRecoveryCodeResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ConsumeRecoveryCodeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConsumeRecoveryCodeRequest, ...grpc.CallOption) (*profile.ConsumeRecoveryCodeResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ConsumeRecoveryCodeRequest, ...grpc.CallOption) *profile.ConsumeRecoveryCodeResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ConsumeRecoveryCodeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ConsumeRecoveryCodeRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateProfile provides a mock function with given fields: ctx, in, opts
 func (_m *ProfileServiceClient) CreateProfile(ctx context.Context, in *profile.CreateProfileRequest, opts ...grpc.CallOption) (*profile.CreateProfileResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -83,6 +314,39 @@ func (_m *ProfileServiceClient) CreateProfile(ctx context.Context, in *profile.C
 	return r0, r1
 }
 
+// CreateWebhookSubscription provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) CreateWebhookSubscription(ctx context.Context, in *profile.CreateWebhookSubscriptionRequest, opts ...grpc.CallOption) (*profile.CreateWebhookSubscriptionResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.CreateWebhookSubscriptionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.CreateWebhookSubscriptionRequest, ...grpc.CallOption) (*profile.CreateWebhookSubscriptionResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.CreateWebhookSubscriptionRequest, ...grpc.CallOption) *profile.CreateWebhookSubscriptionResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.CreateWebhookSubscriptionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.CreateWebhookSubscriptionRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteProfile provides a mock function with given fields: ctx, in, opts
 func (_m *ProfileServiceClient) DeleteProfile(ctx context.Context, in *profile.DeleteProfileRequest, opts ...grpc.CallOption) (*profile.DeleteProfileResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -116,8 +380,8 @@ func (_m *ProfileServiceClient) DeleteProfile(ctx context.Context, in *profile.D
 	return r0, r1
 }
 
-// GetPasswordAndIDByUsername provides a mock function with given fields: ctx, in, opts
-func (_m *ProfileServiceClient) GetPasswordAndIDByUsername(ctx context.Context, in *profile.GetPasswordAndIDByUsernameRequest, opts ...grpc.CallOption) (*profile.GetPasswordAndIDByUsernameResponse, error) {
+// DeleteWebAuthnCredential provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) DeleteWebAuthnCredential(ctx context.Context, in *profile.DeleteWebAuthnCredentialRequest, opts ...grpc.CallOption) (*profile.DeleteWebAuthnCredentialResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -127,20 +391,20 @@ func (_m *ProfileServiceClient) GetPasswordAndIDByUsername(ctx context.Context,
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *profile.GetPasswordAndIDByUsernameResponse
+	var r0 *profile.DeleteWebAuthnCredentialResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) (*profile.GetPasswordAndIDByUsernameResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.DeleteWebAuthnCredentialRequest, ...grpc.CallOption) (*profile.DeleteWebAuthnCredentialResponse, error)); ok {
 		return rf(ctx, in, opts...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) *profile.GetPasswordAndIDByUsernameResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.DeleteWebAuthnCredentialRequest, ...grpc.CallOption) *profile.DeleteWebAuthnCredentialResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*profile.GetPasswordAndIDByUsernameResponse)
+			r0 = ret.Get(0).(*profile.DeleteWebAuthnCredentialResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.DeleteWebAuthnCredentialRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)
@@ -149,8 +413,8 @@ func (_m *ProfileServiceClient) GetPasswordAndIDByUsername(ctx context.Context,
 	return r0, r1
 }
 
-// GetRefreshTokenByID provides a mock function with given fields: ctx, in, opts
-func (_m *ProfileServiceClient) GetRefreshTokenByID(ctx context.Context, in *profile.GetRefreshTokenByIDRequest, opts ...grpc.CallOption) (*profile.GetRefreshTokenByIDResponse, error) {
+// DeleteWebhookSubscription provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) DeleteWebhookSubscription(ctx context.Context, in *profile.DeleteWebhookSubscriptionRequest, opts ...grpc.CallOption) (*profile.DeleteWebhookSubscriptionResponse, error) {
 	_va := make([]interface{}, len(opts))
 	for _i := range opts {
 		_va[_i] = opts[_i]
@@ -160,20 +424,713 @@ func (_m *ProfileServiceClient) GetRefreshTokenByID(ctx context.Context, in *pro
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 *profile.GetRefreshTokenByIDResponse
+	var r0 *profile.DeleteWebhookSubscriptionResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) (*profile.GetRefreshTokenByIDResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.DeleteWebhookSubscriptionRequest, ...grpc.CallOption) (*profile.DeleteWebhookSubscriptionResponse, error)); ok {
 		return rf(ctx, in, opts...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) *profile.GetRefreshTokenByIDResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.DeleteWebhookSubscriptionRequest, ...grpc.CallOption) *profile.DeleteWebhookSubscriptionResponse); ok {
 		r0 = rf(ctx, in, opts...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*profile.GetRefreshTokenByIDResponse)
+			r0 = ret.Get(0).(*profile.DeleteWebhookSubscriptionResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.DeleteWebhookSubscriptionRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishWebAuthnLogin provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) FinishWebAuthnLogin(ctx context.Context, in *profile.FinishWebAuthnLoginRequest, opts ...grpc.CallOption) (*profile.FinishWebAuthnLoginResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.FinishWebAuthnLoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.FinishWebAuthnLoginRequest, ...grpc.CallOption) (*profile.FinishWebAuthnLoginResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.FinishWebAuthnLoginRequest, ...grpc.CallOption) *profile.FinishWebAuthnLoginResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.FinishWebAuthnLoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.FinishWebAuthnLoginRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishWebAuthnRegistration provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) FinishWebAuthnRegistration(ctx context.Context, in *profile.FinishWebAuthnRegistrationRequest, opts ...grpc.CallOption) (*profile.FinishWebAuthnRegistrationResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.FinishWebAuthnRegistrationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.FinishWebAuthnRegistrationRequest, ...grpc.CallOption) (*profile.FinishWebAuthnRegistrationResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.FinishWebAuthnRegistrationRequest, ...grpc.CallOption) *profile.FinishWebAuthnRegistrationResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.FinishWebAuthnRegistrationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.FinishWebAuthnRegistrationRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPasswordAndIDByEmail provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetPasswordAndIDByEmail(ctx context.Context, in *profile.GetPasswordAndIDByEmailRequest, opts ...grpc.CallOption) (*profile.GetPasswordAndIDByEmailResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetPasswordAndIDByEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByEmailRequest, ...grpc.CallOption) (*profile.GetPasswordAndIDByEmailResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByEmailRequest, ...grpc.CallOption) *profile.GetPasswordAndIDByEmailResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetPasswordAndIDByEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetPasswordAndIDByEmailRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPasswordAndIDByPhone provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetPasswordAndIDByPhone(ctx context.Context, in *profile.GetPasswordAndIDByPhoneRequest, opts ...grpc.CallOption) (*profile.GetPasswordAndIDByPhoneResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetPasswordAndIDByPhoneResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByPhoneRequest, ...grpc.CallOption) (*profile.GetPasswordAndIDByPhoneResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByPhoneRequest, ...grpc.CallOption) *profile.GetPasswordAndIDByPhoneResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetPasswordAndIDByPhoneResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetPasswordAndIDByPhoneRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPasswordAndIDByUsername provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetPasswordAndIDByUsername(ctx context.Context, in *profile.GetPasswordAndIDByUsernameRequest, opts ...grpc.CallOption) (*profile.GetPasswordAndIDByUsernameResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetPasswordAndIDByUsernameResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) (*profile.GetPasswordAndIDByUsernameResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) *profile.GetPasswordAndIDByUsernameResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetPasswordAndIDByUsernameResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetPasswordAndIDByUsernameRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPermissions provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetPermissions(ctx context.Context, in *profile.GetPermissionsRequest, opts ...grpc.CallOption) (*profile.GetPermissionsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetPermissionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPermissionsRequest, ...grpc.CallOption) (*profile.GetPermissionsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetPermissionsRequest, ...grpc.CallOption) *profile.GetPermissionsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetPermissionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetPermissionsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProfileByID provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetProfileByID(ctx context.Context, in *profile.GetProfileByIDRequest, opts ...grpc.CallOption) (*profile.GetProfileByIDResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetProfileByIDResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetProfileByIDRequest, ...grpc.CallOption) (*profile.GetProfileByIDResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetProfileByIDRequest, ...grpc.CallOption) *profile.GetProfileByIDResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetProfileByIDResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetProfileByIDRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshTokenByID provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) GetRefreshTokenByID(ctx context.Context, in *profile.GetRefreshTokenByIDRequest, opts ...grpc.CallOption) (*profile.GetRefreshTokenByIDResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.GetRefreshTokenByIDResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) (*profile.GetRefreshTokenByIDResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) *profile.GetRefreshTokenByIDResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.GetRefreshTokenByIDResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.GetRefreshTokenByIDRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueEmailVerificationToken provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) IssueEmailVerificationToken(ctx context.Context, in *profile.IssueEmailVerificationTokenRequest, opts ...grpc.CallOption) (*profile.IssueEmailVerificationTokenResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.IssueEmailVerificationTokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssueEmailVerificationTokenRequest, ...grpc.CallOption) (*profile.IssueEmailVerificationTokenResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssueEmailVerificationTokenRequest, ...grpc.CallOption) *profile.IssueEmailVerificationTokenResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.IssueEmailVerificationTokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.IssueEmailVerificationTokenRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueLoginToken provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) IssueLoginToken(ctx context.Context, in *profile.IssueLoginTokenRequest, opts ...grpc.CallOption) (*profile.IssueLoginTokenResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.IssueLoginTokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssueLoginTokenRequest, ...grpc.CallOption) (*profile.IssueLoginTokenResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssueLoginTokenRequest, ...grpc.CallOption) *profile.IssueLoginTokenResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.IssueLoginTokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.IssueLoginTokenRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssuePhoneVerificationCode provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) IssuePhoneVerificationCode(ctx context.Context, in *profile.IssuePhoneVerificationCodeRequest, opts ...grpc.CallOption) (*profile.IssuePhoneVerificationCodeResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.IssuePhoneVerificationCodeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssuePhoneVerificationCodeRequest, ...grpc.CallOption) (*profile.IssuePhoneVerificationCodeResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.IssuePhoneVerificationCodeRequest, ...grpc.CallOption) *profile.IssuePhoneVerificationCodeResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.IssuePhoneVerificationCodeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.IssuePhoneVerificationCodeRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ListAuditEvents(ctx context.Context, in *profile.ListAuditEventsRequest, opts ...grpc.CallOption) (*profile.ListAuditEventsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ListAuditEventsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListAuditEventsRequest, ...grpc.CallOption) (*profile.ListAuditEventsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListAuditEventsRequest, ...grpc.CallOption) *profile.ListAuditEventsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ListAuditEventsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ListAuditEventsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebAuthnCredentials provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ListWebAuthnCredentials(ctx context.Context, in *profile.ListWebAuthnCredentialsRequest, opts ...grpc.CallOption) (*profile.ListWebAuthnCredentialsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ListWebAuthnCredentialsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebAuthnCredentialsRequest, ...grpc.CallOption) (*profile.ListWebAuthnCredentialsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebAuthnCredentialsRequest, ...grpc.CallOption) *profile.ListWebAuthnCredentialsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ListWebAuthnCredentialsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ListWebAuthnCredentialsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookAttempts provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ListWebhookAttempts(ctx context.Context, in *profile.ListWebhookAttemptsRequest, opts ...grpc.CallOption) (*profile.ListWebhookAttemptsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ListWebhookAttemptsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebhookAttemptsRequest, ...grpc.CallOption) (*profile.ListWebhookAttemptsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebhookAttemptsRequest, ...grpc.CallOption) *profile.ListWebhookAttemptsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ListWebhookAttemptsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ListWebhookAttemptsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookSubscriptions provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) ListWebhookSubscriptions(ctx context.Context, in *profile.ListWebhookSubscriptionsRequest, opts ...grpc.CallOption) (*profile.ListWebhookSubscriptionsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.ListWebhookSubscriptionsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebhookSubscriptionsRequest, ...grpc.CallOption) (*profile.ListWebhookSubscriptionsResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.ListWebhookSubscriptionsRequest, ...grpc.CallOption) *profile.ListWebhookSubscriptionsResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.ListWebhookSubscriptionsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.ListWebhookSubscriptionsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemLoginToken provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) RedeemLoginToken(ctx context.Context, in *profile.RedeemLoginTokenRequest, opts ...grpc.CallOption) (*profile.RedeemLoginTokenResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.RedeemLoginTokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RedeemLoginTokenRequest, ...grpc.CallOption) (*profile.RedeemLoginTokenResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RedeemLoginTokenRequest, ...grpc.CallOption) *profile.RedeemLoginTokenResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.RedeemLoginTokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.RedeemLoginTokenRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) RegenerateRecoveryCodes(ctx context.Context, in *profile.RegenerateRecoveryCodesRequest, opts ...grpc.CallOption) (*profile.RegenerateRecoveryCodesResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.RegenerateRecoveryCodesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RegenerateRecoveryCodesRequest, ...grpc.CallOption) (*profile.RegenerateRecoveryCodesResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RegenerateRecoveryCodesRequest, ...grpc.CallOption) *profile.RegenerateRecoveryCodesResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.RegenerateRecoveryCodesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.RegenerateRecoveryCodesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) RevokeRole(ctx context.Context, in *profile.RevokeRoleRequest, opts ...grpc.CallOption) (*profile.RevokeRoleResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.RevokeRoleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RevokeRoleRequest, ...grpc.CallOption) (*profile.RevokeRoleResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.RevokeRoleRequest, ...grpc.CallOption) *profile.RevokeRoleResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.RevokeRoleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.RevokeRoleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateEmail provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) UpdateEmail(ctx context.Context, in *profile.UpdateEmailRequest, opts ...grpc.CallOption) (*profile.UpdateEmailResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.UpdateEmailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.UpdateEmailRequest, ...grpc.CallOption) (*profile.UpdateEmailResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.UpdateEmailRequest, ...grpc.CallOption) *profile.UpdateEmailResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.UpdateEmailResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.UpdateEmailRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePhone provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) UpdatePhone(ctx context.Context, in *profile.UpdatePhoneRequest, opts ...grpc.CallOption) (*profile.UpdatePhoneResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *profile.UpdatePhoneResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.UpdatePhoneRequest, ...grpc.CallOption) (*profile.UpdatePhoneResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.UpdatePhoneRequest, ...grpc.CallOption) *profile.UpdatePhoneResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*profile.UpdatePhoneResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.UpdatePhoneRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchProfiles provides a mock function with given fields: ctx, in, opts
+func (_m *ProfileServiceClient) WatchProfiles(ctx context.Context, in *profile.WatchProfilesRequest, opts ...grpc.CallOption) (profile.ProfileService_WatchProfilesClient, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 profile.ProfileService_WatchProfilesClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.WatchProfilesRequest, ...grpc.CallOption) (profile.ProfileService_WatchProfilesClient, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *profile.WatchProfilesRequest, ...grpc.CallOption) profile.ProfileService_WatchProfilesClient); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(profile.ProfileService_WatchProfilesClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *profile.WatchProfilesRequest, ...grpc.CallOption) error); ok {
 		r1 = rf(ctx, in, opts...)
 	} else {
 		r1 = ret.Error(1)